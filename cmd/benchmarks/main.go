@@ -0,0 +1,58 @@
+// Command benchmarks runs the framework benchmarks and compares them with
+// a baseline:
+//
+//	go run ./cmd/benchmarks -write              # record a new baseline
+//	go run ./cmd/benchmarks -tolerance 0.15     # fail on >15% slowdowns
+//	go run ./cmd/benchmarks -tolerance -1       # only fail on extra allocations
+//	go run ./cmd/benchmarks -markdown > BENCH.md
+//
+// Allocation counts are deterministic and any increase fails. Timings are
+// noisy, so the default tolerance is loose and baselines are machine
+// specific; record one on the machine you compare on.
+package main
+
+import (
+	"errors"
+	"flag"
+	"io/fs"
+	"log"
+	"os"
+
+	"github.com/calummacc/goblin/internal/benchmarks"
+)
+
+func main() {
+	baselinePath := flag.String("baseline", "internal/benchmarks/baseline.json", "baseline results file")
+	write := flag.Bool("write", false, "write the results as the new baseline")
+	tolerance := flag.Float64("tolerance", 0.50, "allowed slowdown before failing, 0.50 is 50%, negative ignores timings")
+	markdown := flag.Bool("markdown", false, "only print the results table, without failing on regressions")
+	filter := flag.String("run", "", "only run benchmarks matching this regexp")
+	count := flag.Int("count", 3, "runs per benchmark, the fastest is kept")
+	flag.Parse()
+
+	results, err := benchmarks.Run(*filter, *count)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *write {
+		if err := benchmarks.WriteBaseline(*baselinePath, results); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	baseline, err := benchmarks.ReadBaseline(*baselinePath)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		log.Fatal(err)
+	}
+	benchmarks.Markdown(os.Stdout, results, baseline)
+	if *markdown {
+		return
+	}
+	if regressions := benchmarks.Compare(baseline, results, *tolerance); len(regressions) > 0 {
+		os.Stdout.WriteString("\nRegressions:\n")
+		benchmarks.Text(os.Stdout, regressions)
+		os.Exit(1)
+	}
+}
@@ -10,16 +10,20 @@ import (
 
 type AppModule struct {
 	core.BaseModule
+	options    core.ApplicationOptions
+	ids        core.IDGenerator
 	userModule *user.UserModule
 }
 
-func NewAppModule() *AppModule {
+func NewAppModule(options core.ApplicationOptions) *AppModule {
 	return &AppModule{
+		options:    options,
 		userModule: user.NewUserModule(),
 	}
 }
 
 func (m *AppModule) Configure(container *core.Container) {
+	m.ids = core.ResolveIDGenerator(container)
 	m.userModule.Configure(container)
 }
 
@@ -29,18 +33,24 @@ func (m *AppModule) ProvideDependencies() fx.Option {
 	)
 }
 
-func (m *AppModule) RegisterRoutes(router *gin.RouterGroup) {
-	// Add middleware
-	router.Use(
+func (m *AppModule) RoutePrefix() string {
+	return "/api/v1"
+}
+
+func (m *AppModule) Middleware() []gin.HandlerFunc {
+	middlewares := []gin.HandlerFunc{
 		middleware.Logger(),
 		middleware.Recovery(),
-		middleware.RequestID(),
+		middleware.RequestID(m.ids),
 		middleware.ErrorHandler(),
-	)
+	}
 
+	// Cap concurrent requests per client outside of tests
+	limiter := middleware.NewConcurrencyLimiter(8, middleware.KeyByIP())
+	return append(middlewares, core.UseIf(m.options, core.ExceptInProfiles("test"), limiter.Middleware())...)
+}
+
+func (m *AppModule) RegisterRoutes(router *gin.RouterGroup) {
 	// Register API routes
-	api := router.Group("/api/v1")
-	{
-		m.userModule.RegisterRoutes(api)
-	}
+	m.userModule.RegisterRoutes(router)
 }
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/calummacc/goblin/examples/basic/modules/user"
+	"github.com/calummacc/goblin/internal/core"
+	"github.com/calummacc/goblin/internal/openapi"
+	"github.com/gin-gonic/gin"
+)
+
+// newAPIDocs serves the OpenAPI spec at /openapi.json and Swagger UI at
+// /docs, with the user routes described
+func newAPIDocs(app *core.Application) *openapi.OpenAPIModule {
+	docs := openapi.NewOpenAPIModule(app,
+		openapi.WithInfo("Goblin example", "1.0.0"),
+		openapi.WithSwaggerUI("/docs"),
+	)
+
+	errorBody := gin.H{}
+	docs.Describe("GET /api/v1/users", openapi.Operation{
+		Summary:   "List users",
+		Tags:      []string{"users"},
+		Responses: map[int]interface{}{http.StatusOK: core.CursorPage[user.User]{}},
+	})
+	docs.Describe("GET /api/v1/users/:id", openapi.Operation{
+		Summary: "Get a user",
+		Tags:    []string{"users"},
+		Responses: map[int]interface{}{
+			http.StatusOK:       user.User{},
+			http.StatusNotFound: errorBody,
+		},
+	})
+	docs.Describe("POST /api/v1/users", openapi.Operation{
+		Summary: "Create a user",
+		Tags:    []string{"users"},
+		Request: user.UserRequest{},
+		Responses: map[int]interface{}{
+			http.StatusCreated:    user.User{},
+			http.StatusBadRequest: errorBody,
+		},
+	})
+	docs.Describe("DELETE /api/v1/users/:id", openapi.Operation{
+		Summary: "Delete a user",
+		Tags:    []string{"users"},
+		Responses: map[int]interface{}{
+			http.StatusNoContent:            nil,
+			http.StatusPreconditionFailed:   errorBody,
+			http.StatusPreconditionRequired: errorBody,
+		},
+	})
+	return docs
+}
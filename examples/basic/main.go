@@ -3,25 +3,92 @@ package main
 import (
 	"context"
 	"log"
+	"os"
+	"time"
 
+	"github.com/calummacc/goblin/internal/admin"
+	"github.com/calummacc/goblin/internal/config"
 	"github.com/calummacc/goblin/internal/core"
+	"github.com/calummacc/goblin/internal/health"
+	"github.com/calummacc/goblin/internal/middleware"
 	"github.com/gin-gonic/gin"
 )
 
 func main() {
+	// Read configuration from the environment and optional .env and
+	// .env.<profile> files, reloading the files on SIGHUP
+	configModule := config.ForRoot(
+		config.WithEnvPrefix("GOBLIN_"),
+		config.WithProfile(os.Getenv("GOBLIN_PROFILE")),
+		config.WithReloadOnSignal(),
+	)
+	cfg := configModule.Config()
+	port, err := cfg.Int("port", 3000)
+	if err != nil {
+		log.Fatal(err)
+	}
+	maintenanceEnabled, err := cfg.Bool("maintenance", false)
+	if err != nil {
+		log.Fatal(err)
+	}
+	logLevel, err := core.ParseLogLevel(cfg.String("log.level", "info"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	node, err := cfg.Int("node", 1)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Numeric, time-ordered IDs for users and requests; node must be unique
+	// per running instance
+	ids, err := core.NewSnowflake(int64(node), nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	// Create new application with custom configuration
 	app := core.NewGoblinApplication(
-		core.WithPort(3000),
-		core.WithHost("0.0.0.0"),
+		core.WithPort(port),
+		core.WithHost(cfg.String("host", "0.0.0.0")),
 		core.WithGinMode(gin.ReleaseMode),
+		core.WithIDGenerator(ids),
+		core.WithLogLevel(logLevel),
+		core.WithShutdownSignals(),
 	)
 
+	// Follow log.level when the configuration is reloaded
+	cfg.OnChange(func(event config.ChangeEvent) {
+		if !event.Has("log.level") {
+			return
+		}
+		if level, err := core.ParseLogLevel(cfg.String("log.level", "info")); err == nil {
+			app.Logger().SetLevel(level)
+		}
+	})
+
+	// Serve 503 on application routes while in maintenance
+	maintenance := middleware.NewMaintenanceMode(time.Minute, "/admin", "/health")
+	if maintenanceEnabled {
+		maintenance.Enable()
+	}
+	app.GetEngine().Use(maintenance.Middleware())
+
 	// Add modules
-	appModule := NewAppModule()
+	app.AddModule(configModule)
+	appModule := NewAppModule(app.GetConfig())
 	app.AddModule(appModule)
+	app.AddModule(health.NewHealthModule(app))
+	app.AddModule(newAPIDocs(app))
+	app.AddModule(admin.NewAdminModule(app,
+		admin.WithToken(cfg.String("admin.token", "")),
+		admin.WithMaintenance(maintenance),
+	))
 
 	// Configure application
-	app.Configure()
+	if err := app.Configure(); err != nil {
+		log.Fatal(err)
+	}
 
 	// Run application
 	if err := app.Run(context.Background()); err != nil {
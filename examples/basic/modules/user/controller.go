@@ -1,9 +1,15 @@
 package user
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"strconv"
 
+	"github.com/calummacc/goblin/internal/core"
+	"github.com/calummacc/goblin/internal/patch"
+	"github.com/calummacc/goblin/internal/query"
+	"github.com/calummacc/goblin/internal/validation"
 	"github.com/gin-gonic/gin"
 )
 
@@ -11,106 +17,279 @@ type Controller struct {
 	service Service
 }
 
-type CreateUserRequest struct {
-	Username string `json:"username" binding:"required"`
-	Email    string `json:"email" binding:"required,email"`
-}
-
-type UpdateUserRequest struct {
-	Username string `json:"username,omitempty"`
-	Email    string `json:"email,omitempty" binding:"omitempty,email"`
+// UserRequest is the body of create and update requests. Every field is
+// required on create and optional on update.
+type UserRequest struct {
+	Username string `json:"username,omitempty" create:"required"`
+	Email    string `json:"email,omitempty" binding:"omitempty,email" create:"required"`
 }
 
 func NewController(service Service) *Controller {
 	return &Controller{service: service}
 }
 
+// userCursor is the keyset position encoded into list cursors
+type userCursor struct {
+	ID        uint   `json:"id"`
+	Backwards bool   `json:"b,omitempty"`
+	Sort      string `json:"s,omitempty"` // The ?sort= the page was listed in
+}
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+var userQuery = query.NewSchema(User{})
+
+// userFilter turns a parsed ?filter= and ?sort= into the repository's
+// match and order functions; less is nil when no sort was asked for
+func userFilter(q *query.Query) (match func(User) bool, less func(a, b User) bool) {
+	match = func(user User) bool { return userQuery.Match(q, user) }
+	if len(q.Sort) > 0 {
+		less = func(a, b User) bool { return userQuery.Less(q, a, b) }
+	}
+	return match, less
+}
+
 func (c *Controller) GetUsers(ctx *gin.Context) {
-	users, err := c.service.GetAllUsers()
+	limit := defaultPageSize
+	if raw := ctx.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > maxPageSize {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+			return
+		}
+		limit = n
+	}
+
+	var cursor userCursor
+	if raw := ctx.Query("cursor"); raw != "" {
+		if err := core.DecodeCursor(raw, &cursor); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	filter, err := userQuery.FromContext(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if cursor.ID != 0 && cursor.Sort != ctx.Query("sort") {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "cursor was issued for another sort"})
 		return
 	}
-	ctx.JSON(http.StatusOK, users)
+	match, less := userFilter(filter)
+
+	users, more, err := c.service.ListUsers(ctx.Request.Context(), cursor.ID, cursor.Backwards, limit, match, less)
+	if err != nil {
+		ctx.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	page := core.CursorPage[User]{Items: users}
+	if len(users) > 0 {
+		first, last := users[0].ID, users[len(users)-1].ID
+		hasNext, hasPrev := more, cursor.ID != 0
+		if cursor.Backwards {
+			hasNext, hasPrev = true, more
+		}
+		if hasNext {
+			page.NextCursor, _ = core.EncodeCursor(userCursor{ID: last, Sort: ctx.Query("sort")})
+		}
+		if hasPrev {
+			page.PrevCursor, _ = core.EncodeCursor(userCursor{ID: first, Backwards: true, Sort: ctx.Query("sort")})
+		}
+	}
+
+	ctx.JSON(http.StatusOK, page)
+}
+
+// ExportUsers streams every user matching the filter, walking the
+// repository page by page instead of loading the whole table
+func (c *Controller) ExportUsers(ctx *gin.Context) {
+	filter, err := userQuery.FromContext(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	match, less := userFilter(filter)
+
+	stream := core.StreamNDJSON
+	if ctx.Query("format") == "json" {
+		stream = core.StreamJSONArray
+	}
+
+	err = stream(ctx, func(w core.ItemWriter) error {
+		var after uint
+		for {
+			users, more, err := c.service.ListUsers(ctx.Request.Context(), after, false, maxPageSize, match, less)
+			if err != nil {
+				return err
+			}
+			for _, user := range users {
+				if err := w.Write(user); err != nil {
+					return err
+				}
+			}
+			if !more {
+				return nil
+			}
+			after = users[len(users)-1].ID
+		}
+	})
+	if err != nil && !ctx.Writer.Written() {
+		ctx.JSON(errorStatus(err), gin.H{"error": err.Error()})
+	}
 }
 
 func (c *Controller) GetUser(ctx *gin.Context) {
-	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
 		return
 	}
 
-	user, err := c.service.GetUserByID(uint(id))
+	user, err := c.service.GetUserByID(ctx.Request.Context(), uint(id))
 	if err != nil {
-		status := http.StatusInternalServerError
-		if err == ErrUserNotFound {
-			status = http.StatusNotFound
-		}
-		ctx.JSON(status, gin.H{"error": err.Error()})
+		ctx.JSON(errorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
+	core.SetETag(ctx, user)
 	ctx.JSON(http.StatusOK, user)
 }
 
 func (c *Controller) CreateUser(ctx *gin.Context) {
-	var req CreateUserRequest
-	if err := ctx.ShouldBindJSON(&req); err != nil {
+	var req UserRequest
+	if err := validation.BindJSON(ctx, &req); err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	user, err := c.service.CreateUser(req.Username, req.Email)
+	user, err := c.service.CreateUser(ctx.Request.Context(), req.Username, req.Email)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.JSON(errorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
+	if location, err := core.RouteURL(ctx, "user.Controller.GetUser", user.ID); err == nil {
+		ctx.Header("Location", location)
+	}
+
 	ctx.JSON(http.StatusCreated, user)
 }
 
 func (c *Controller) UpdateUser(ctx *gin.Context) {
-	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
 		return
 	}
 
-	var req UpdateUserRequest
-	if err := ctx.ShouldBindJSON(&req); err != nil {
+	user, err := c.service.GetUserByID(ctx.Request.Context(), uint(id))
+	if err != nil {
+		ctx.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	if !core.CheckIfMatch(ctx, user) {
+		return
+	}
+
+	var req UserRequest
+	if err := validation.BindJSON(ctx, &req); err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	user, err := c.service.UpdateUser(uint(id), req.Username, req.Email)
+	// The write only goes ahead if nobody changed the user since the
+	// If-Match check
+	user, err = c.service.UpdateUser(ctx.Request.Context(), uint(id), req.Username, req.Email, user.Version())
 	if err != nil {
-		status := http.StatusInternalServerError
-		if err == ErrUserNotFound {
-			status = http.StatusNotFound
-		}
-		ctx.JSON(status, gin.H{"error": err.Error()})
+		ctx.JSON(errorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
+	core.SetETag(ctx, user)
+	ctx.JSON(http.StatusOK, user)
+}
+
+// PatchUser applies a JSON Merge Patch or JSON Patch to the user. A JSON
+// Patch "test" that no longer matches answers 409 Conflict.
+func (c *Controller) PatchUser(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	user, err := c.service.GetUserByID(ctx.Request.Context(), uint(id))
+	if err != nil {
+		ctx.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	if !core.CheckIfMatch(ctx, user) {
+		return
+	}
+
+	req := UserRequest{Username: user.Username, Email: user.Email}
+	if err := patch.Apply(ctx, &req); err != nil {
+		ctx.JSON(patch.Status(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err = c.service.UpdateUser(ctx.Request.Context(), uint(id), req.Username, req.Email, user.Version())
+	if err != nil {
+		ctx.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	core.SetETag(ctx, user)
 	ctx.JSON(http.StatusOK, user)
 }
 
 func (c *Controller) DeleteUser(ctx *gin.Context) {
-	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
 		return
 	}
 
-	if err := c.service.DeleteUser(uint(id)); err != nil {
-		status := http.StatusInternalServerError
-		if err == ErrUserNotFound {
-			status = http.StatusNotFound
-		}
-		ctx.JSON(status, gin.H{"error": err.Error()})
+	user, err := c.service.GetUserByID(ctx.Request.Context(), uint(id))
+	if err != nil {
+		ctx.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	if !core.CheckIfMatch(ctx, user) {
+		return
+	}
+
+	if err := c.service.DeleteUser(ctx.Request.Context(), uint(id), user.Version()); err != nil {
+		ctx.JSON(errorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
 	ctx.Status(http.StatusNoContent)
 }
+
+// errorStatus maps service errors to HTTP status codes
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, ErrUserNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrUserExists):
+		return http.StatusConflict
+	case errors.Is(err, ErrUserModified):
+		return http.StatusPreconditionFailed
+	case errors.Is(err, ErrStaleCursor):
+		return http.StatusBadRequest
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	case errors.Is(err, context.Canceled):
+		return http.StatusRequestTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
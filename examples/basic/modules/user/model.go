@@ -1,13 +1,19 @@
 package user
 
 import (
+	"strconv"
 	"time"
 )
 
 type User struct {
-	ID        uint      `json:"id"`
-	Username  string    `json:"username"`
-	Email     string    `json:"email"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID        uint      `json:"id,string"` // Snowflake IDs exceed what JavaScript numbers hold
+	Username  string    `json:"username" query:"filter,sort"`
+	Email     string    `json:"email" query:"filter"`
+	CreatedAt time.Time `json:"created_at" query:"filter,sort"`
+	UpdatedAt time.Time `json:"updated_at" query:"sort"`
+}
+
+// Version changes on every update and backs the user's ETag
+func (u *User) Version() string {
+	return strconv.FormatInt(u.UpdatedAt.UnixNano(), 36)
 }
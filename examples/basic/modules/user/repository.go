@@ -1,24 +1,33 @@
 package user
 
 import (
+	"context"
 	"errors"
+	"sort"
+	"sync"
 )
 
 var (
 	ErrUserNotFound = errors.New("user not found")
 	ErrUserExists   = errors.New("user already exists")
+	ErrUserModified = errors.New("user has been modified")
+	ErrStaleCursor  = errors.New("cursor no longer points at a user")
 )
 
 type Repository interface {
-	FindAll() ([]User, error)
-	FindByID(id uint) (*User, error)
-	Create(user *User) error
-	Update(user *User) error
-	Delete(id uint) error
+	FindAll(ctx context.Context) ([]User, error)
+	FindPage(ctx context.Context, id uint, backwards bool, limit int, match func(User) bool, less func(a, b User) bool) ([]User, bool, error)
+	FindByID(ctx context.Context, id uint) (*User, error)
+	Create(ctx context.Context, user *User) error
+	// Update and Delete fail with ErrUserModified unless the stored user
+	// is still at version; an empty version skips the check
+	Update(ctx context.Context, user *User, version string) error
+	Delete(ctx context.Context, id uint, version string) error
 }
 
 type repository struct {
 	// In a real application, you would have your database connection here
+	mu    sync.RWMutex
 	users map[uint]*User
 }
 
@@ -28,7 +37,14 @@ func NewRepository() Repository {
 	}
 }
 
-func (r *repository) FindAll() ([]User, error) {
+func (r *repository) FindAll(ctx context.Context) ([]User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
 	users := make([]User, 0, len(r.users))
 	for _, user := range r.users {
 		users = append(users, *user)
@@ -36,33 +52,126 @@ func (r *repository) FindAll() ([]User, error) {
 	return users, nil
 }
 
-func (r *repository) FindByID(id uint) (*User, error) {
+// FindPage returns up to limit users in the order of less, starting after
+// the user id, or ending before it when backwards is set. Users less does
+// not order, and all users when it is nil, are ordered by ID. Only users
+// accepted by match are included when it is set. The second result reports
+// whether more users exist beyond the page.
+func (r *repository) FindPage(ctx context.Context, id uint, backwards bool, limit int, match func(User) bool, less func(a, b User) bool) ([]User, bool, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, false, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	before := func(a, b User) bool {
+		if less != nil {
+			if less(a, b) {
+				return true
+			}
+			if less(b, a) {
+				return false
+			}
+		}
+		return a.ID < b.ID
+	}
+	// Ordered by ID alone, the page boundary does not need to exist anymore
+	boundary := User{ID: id}
+	if id != 0 && less != nil {
+		stored, exists := r.users[id]
+		if !exists {
+			return nil, false, ErrStaleCursor
+		}
+		boundary = *stored
+	}
+
+	users := make([]User, 0, len(r.users))
+	for _, user := range r.users {
+		if id != 0 && ((!backwards && !before(boundary, *user)) || (backwards && !before(*user, boundary))) {
+			continue
+		}
+		if match == nil || match(*user) {
+			users = append(users, *user)
+		}
+	}
+	sort.Slice(users, func(i, j int) bool { return before(users[i], users[j]) })
+
+	if len(users) <= limit {
+		return users, false, nil
+	}
+	if backwards {
+		return users[len(users)-limit:], true, nil
+	}
+	return users[:limit], true, nil
+}
+
+func (r *repository) FindByID(ctx context.Context, id uint) (*User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
 	if user, exists := r.users[id]; exists {
-		return user, nil
+		found := *user
+		return &found, nil
 	}
 	return nil, ErrUserNotFound
 }
 
-func (r *repository) Create(user *User) error {
+func (r *repository) Create(ctx context.Context, user *User) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	if _, exists := r.users[user.ID]; exists {
 		return ErrUserExists
 	}
-	r.users[user.ID] = user
+	stored := *user
+	r.users[user.ID] = &stored
 	return nil
 }
 
-func (r *repository) Update(user *User) error {
-	if _, exists := r.users[user.ID]; !exists {
+func (r *repository) Update(ctx context.Context, user *User, version string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, exists := r.users[user.ID]
+	if !exists {
 		return ErrUserNotFound
 	}
-	r.users[user.ID] = user
+	if version != "" && stored.Version() != version {
+		return ErrUserModified
+	}
+	updated := *user
+	r.users[user.ID] = &updated
 	return nil
 }
 
-func (r *repository) Delete(id uint) error {
-	if _, exists := r.users[id]; !exists {
+func (r *repository) Delete(ctx context.Context, id uint, version string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, exists := r.users[id]
+	if !exists {
 		return ErrUserNotFound
 	}
+	if version != "" && stored.Version() != version {
+		return ErrUserModified
+	}
 	delete(r.users, id)
 	return nil
 }
@@ -1,65 +1,86 @@
 package user
 
 import (
-	"time"
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/calummacc/goblin/internal/core"
 )
 
 type Service interface {
-	GetAllUsers() ([]User, error)
-	GetUserByID(id uint) (*User, error)
-	CreateUser(username, email string) (*User, error)
-	UpdateUser(id uint, username, email string) (*User, error)
-	DeleteUser(id uint) error
+	GetAllUsers(ctx context.Context) ([]User, error)
+	ListUsers(ctx context.Context, id uint, backwards bool, limit int, match func(User) bool, less func(a, b User) bool) ([]User, bool, error)
+	GetUserByID(ctx context.Context, id uint) (*User, error)
+	CreateUser(ctx context.Context, username, email string) (*User, error)
+	// UpdateUser and DeleteUser fail with ErrUserModified when the user
+	// is no longer at version, e.g. the version a client's If-Match was
+	// checked against; an empty version skips the check
+	UpdateUser(ctx context.Context, id uint, username, email, version string) (*User, error)
+	DeleteUser(ctx context.Context, id uint, version string) error
 }
 
 type service struct {
-	repo Repository
+	repo  Repository
+	clock core.Clock
+	ids   core.IDGenerator
+}
+
+// NewService takes user IDs from ids, which must produce unsigned integers,
+// e.g. a core.Snowflake or a SequenceIDGenerator without prefix
+func NewService(repo Repository, clock core.Clock, ids core.IDGenerator) Service {
+	return &service{repo: repo, clock: clock, ids: ids}
 }
 
-func NewService(repo Repository) Service {
-	return &service{repo: repo}
+func (s *service) GetAllUsers(ctx context.Context) ([]User, error) {
+	return s.repo.FindAll(ctx)
 }
 
-func (s *service) GetAllUsers() ([]User, error) {
-	return s.repo.FindAll()
+func (s *service) ListUsers(ctx context.Context, id uint, backwards bool, limit int, match func(User) bool, less func(a, b User) bool) ([]User, bool, error) {
+	return s.repo.FindPage(ctx, id, backwards, limit, match, less)
 }
 
-func (s *service) GetUserByID(id uint) (*User, error) {
-	return s.repo.FindByID(id)
+func (s *service) GetUserByID(ctx context.Context, id uint) (*User, error) {
+	return s.repo.FindByID(ctx, id)
 }
 
-func (s *service) CreateUser(username, email string) (*User, error) {
-	now := time.Now()
+func (s *service) CreateUser(ctx context.Context, username, email string) (*User, error) {
+	id, err := strconv.ParseUint(s.ids.NewID(), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("user IDs must be numeric: %w", err)
+	}
+
+	now := s.clock.Now()
 	user := &User{
-		ID:        uint(now.UnixNano()),
+		ID:        uint(id),
 		Username:  username,
 		Email:     email,
 		CreatedAt: now,
 		UpdatedAt: now,
 	}
 
-	if err := s.repo.Create(user); err != nil {
+	if err := s.repo.Create(ctx, user); err != nil {
 		return nil, err
 	}
 	return user, nil
 }
 
-func (s *service) UpdateUser(id uint, username, email string) (*User, error) {
-	user, err := s.repo.FindByID(id)
+func (s *service) UpdateUser(ctx context.Context, id uint, username, email, version string) (*User, error) {
+	user, err := s.repo.FindByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
 	user.Username = username
 	user.Email = email
-	user.UpdatedAt = time.Now()
+	user.UpdatedAt = s.clock.Now()
 
-	if err := s.repo.Update(user); err != nil {
+	if err := s.repo.Update(ctx, user, version); err != nil {
 		return nil, err
 	}
 	return user, nil
 }
 
-func (s *service) DeleteUser(id uint) error {
-	return s.repo.Delete(id)
+func (s *service) DeleteUser(ctx context.Context, id uint, version string) error {
+	return s.repo.Delete(ctx, id, version)
 }
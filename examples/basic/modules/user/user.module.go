@@ -2,6 +2,7 @@ package user
 
 import (
 	"github.com/calummacc/goblin/internal/core"
+	"github.com/calummacc/goblin/internal/validation"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/fx"
 )
@@ -19,12 +20,12 @@ func NewUserModule() *UserModule {
 
 func (m *UserModule) Configure(container *core.Container) {
 	m.repository = NewRepository()
-	m.service = NewService(m.repository)
+	m.service = NewService(m.repository, core.ResolveClock(container), core.ResolveIDGenerator(container))
 	m.controller = NewController(m.service)
 }
 
 func (m *UserModule) ProvideDependencies() fx.Option {
-	return fx.Options(
+	return fx.Module("user",
 		fx.Provide(
 			NewRepository,
 			NewService,
@@ -37,9 +38,11 @@ func (m *UserModule) RegisterRoutes(router *gin.RouterGroup) {
 	users := router.Group("/users")
 	{
 		users.GET("", m.controller.GetUsers)
+		users.GET("/export", m.controller.ExportUsers)
 		users.GET("/:id", m.controller.GetUser)
-		users.POST("", m.controller.CreateUser)
-		users.PUT("/:id", m.controller.UpdateUser)
-		users.DELETE("/:id", m.controller.DeleteUser)
+		users.POST("", validation.Group("create"), m.controller.CreateUser)
+		users.PUT("/:id", core.RequireIfMatch(), validation.Group("update"), m.controller.UpdateUser)
+		users.PATCH("/:id", core.RequireIfMatch(), validation.Group("update"), m.controller.PatchUser)
+		users.DELETE("/:id", core.RequireIfMatch(), m.controller.DeleteUser)
 	}
 }
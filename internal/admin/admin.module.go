@@ -0,0 +1,291 @@
+package admin
+
+import (
+	"crypto/subtle"
+	"expvar"
+	"net/http"
+	"net/http/pprof"
+	"strings"
+	"time"
+
+	"github.com/calummacc/goblin/internal/config"
+	"github.com/calummacc/goblin/internal/core"
+	"github.com/calummacc/goblin/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+type Options struct {
+	Prefix string     // Prefix the admin endpoints are mounted under
+	Token  string     // Bearer token required by the default guard
+	Guard  core.Guard // Replaces the default bearer token guard
+
+	StatsInterval time.Duration // How often runtime stats are sampled, 0 disables
+	Thresholds    Thresholds    // Runtime stats that trigger warning logs
+
+	Maintenance *middleware.MaintenanceMode // Switch toggled by /maintenance
+}
+
+var defaultOptions = Options{
+	Prefix:        "/admin",
+	StatsInterval: 15 * time.Second,
+}
+
+func WithPrefix(prefix string) func(*Options) {
+	return func(opts *Options) {
+		opts.Prefix = prefix
+	}
+}
+
+func WithToken(token string) func(*Options) {
+	return func(opts *Options) {
+		opts.Token = token
+	}
+}
+
+func WithGuard(guard core.Guard) func(*Options) {
+	return func(opts *Options) {
+		opts.Guard = guard
+	}
+}
+
+func WithRuntimeStats(interval time.Duration, thresholds Thresholds) func(*Options) {
+	return func(opts *Options) {
+		opts.StatsInterval = interval
+		opts.Thresholds = thresholds
+	}
+}
+
+func WithMaintenance(maintenance *middleware.MaintenanceMode) func(*Options) {
+	return func(opts *Options) {
+		opts.Maintenance = maintenance
+	}
+}
+
+// AdminModule exposes operational endpoints (route table, configuration,
+// log level, pprof profiles, expvar and runtime stats) for the application
+// it is added to.
+type AdminModule struct {
+	core.BaseModule
+	app       *core.Application
+	options   Options
+	collector *RuntimeCollector
+}
+
+func NewAdminModule(app *core.Application, opts ...func(*Options)) *AdminModule {
+	options := defaultOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	collector := NewRuntimeCollector(options.StatsInterval, options.Thresholds)
+	collector.logger = app.Logger()
+	return &AdminModule{
+		app:       app,
+		options:   options,
+		collector: collector,
+	}
+}
+
+func (m *AdminModule) OnInit() error {
+	m.collector.Start()
+	return nil
+}
+
+func (m *AdminModule) OnDestroy() error {
+	m.collector.Stop()
+	return nil
+}
+
+func (m *AdminModule) RegisterRoutes(router *gin.RouterGroup) {
+	guard := m.options.Guard
+	if guard == nil {
+		guard = m.tokenGuard()
+	}
+
+	admin := router.Group(m.options.Prefix, core.GuardToMiddleware(guard))
+	{
+		admin.GET("/routes", m.getRoutes)
+		admin.GET("/config", m.getConfig)
+		admin.GET("/runtime", m.getRuntime)
+		admin.GET("/log-level", m.getLogLevel)
+		admin.PUT("/log-level", m.setLogLevel)
+		if m.app.Profiler() != nil {
+			admin.GET("/pipeline", m.getPipeline)
+		}
+		admin.GET("/vars", gin.WrapH(expvar.Handler()))
+		admin.GET("/pprof/", gin.WrapF(pprof.Index))
+		admin.GET("/pprof/:name", m.getProfile)
+
+		if m.options.Maintenance != nil {
+			admin.GET("/maintenance", m.getMaintenance)
+			admin.PUT("/maintenance", m.setMaintenance)
+		}
+	}
+}
+
+// tokenGuard only lets through requests carrying the configured bearer
+// token. Without a token every request is rejected.
+func (m *AdminModule) tokenGuard() core.Guard {
+	expected := []byte(m.options.Token)
+
+	return core.GuardFunc(func(c *gin.Context) (bool, error) {
+		token, found := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !found || len(expected) == 0 {
+			return false, core.Unauthorized(`Bearer realm="admin"`)
+		}
+		return subtle.ConstantTimeCompare([]byte(token), expected) == 1, nil
+	})
+}
+
+// getRoutes lists the routes with their handler, and with the full
+// resolved chain when ?pipeline=true
+func (m *AdminModule) getRoutes(ctx *gin.Context) {
+	routes := m.app.GetEngine().Routes()
+	withPipeline := ctx.Query("pipeline") == "true"
+
+	table := make([]gin.H, 0, len(routes))
+	for _, route := range routes {
+		entry := gin.H{
+			"method":  route.Method,
+			"path":    route.Path,
+			"handler": route.Handler,
+		}
+		if withPipeline {
+			if pipeline, err := m.app.Pipeline(route.Method + " " + route.Path); err == nil {
+				entry["pipeline"] = pipeline.Handlers
+			}
+		}
+		table = append(table, entry)
+	}
+	ctx.JSON(http.StatusOK, table)
+}
+
+// sensitiveWords mark configuration keys whose values /config masks
+var sensitiveWords = []string{"token", "secret", "password", "key"}
+
+// getConfig dumps the application options worth seeing in operations,
+// leaving out internals and certificate paths, and the loaded
+// configuration with secrets masked
+func (m *AdminModule) getConfig(ctx *gin.Context) {
+	opts := m.app.GetConfig()
+	signals := make([]string, 0, len(opts.ShutdownSignals))
+	for _, signal := range opts.ShutdownSignals {
+		signals = append(signals, signal.String())
+	}
+
+	view := gin.H{"application": gin.H{
+		"host":                opts.Host,
+		"port":                opts.Port,
+		"gin_mode":            opts.GinMode,
+		"profile":             opts.Profile,
+		"enabled_modules":     opts.EnabledModules,
+		"trailing_slash":      opts.RoutingPolicy.TrailingSlash,
+		"case_insensitive":    opts.RoutingPolicy.CaseInsensitive,
+		"method_override":     opts.MethodOverride,
+		"auto_head":           opts.AutoHead,
+		"shutdown_signals":    signals,
+		"drain_timeout":       opts.DrainTimeout.String(),
+		"read_timeout":        opts.ReadTimeout.String(),
+		"read_header_timeout": opts.ReadHeaderTimeout.String(),
+		"write_timeout":       opts.WriteTimeout.String(),
+		"idle_timeout":        opts.IdleTimeout.String(),
+		"tls":                 opts.TLSCertFile != "" || opts.TLSConfig != nil,
+		"profiling":           opts.Profiling,
+		"log_level":           m.app.Logger().Level().String(),
+	}}
+
+	if cfg, err := core.Resolve[*config.ConfigService](m.app.GetContainer()); err == nil {
+		values := make(map[string]string)
+		for _, key := range cfg.Keys() {
+			value, _ := cfg.Get(key)
+			if sensitive(key) {
+				value = "[REDACTED]"
+			}
+			values[key] = value
+		}
+		view["config"] = values
+	}
+	ctx.JSON(http.StatusOK, view)
+}
+
+func sensitive(key string) bool {
+	key = strings.ToLower(key)
+	for _, word := range sensitiveWords {
+		if strings.Contains(key, word) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *AdminModule) getRuntime(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, m.collector.Stats())
+}
+
+func (m *AdminModule) getPipeline(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, m.app.Profiler().Snapshot())
+}
+
+type logLevelRequest struct {
+	Level string `json:"level" binding:"required"`
+}
+
+func (m *AdminModule) getLogLevel(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"level": m.app.Logger().Level().String()})
+}
+
+// setLogLevel changes the level of the application logger until the next
+// change or restart
+func (m *AdminModule) setLogLevel(ctx *gin.Context) {
+	var req logLevelRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	level, err := core.ParseLogLevel(req.Level)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	m.app.Logger().SetLevel(level)
+	ctx.JSON(http.StatusOK, gin.H{"level": level.String()})
+}
+
+type maintenanceRequest struct {
+	Enabled *bool `json:"enabled" binding:"required"`
+}
+
+func (m *AdminModule) getMaintenance(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"enabled": m.options.Maintenance.Enabled()})
+}
+
+func (m *AdminModule) setMaintenance(ctx *gin.Context) {
+	var req maintenanceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if *req.Enabled {
+		m.options.Maintenance.Enable()
+	} else {
+		m.options.Maintenance.Disable()
+	}
+	ctx.JSON(http.StatusOK, gin.H{"enabled": *req.Enabled})
+}
+
+func (m *AdminModule) getProfile(ctx *gin.Context) {
+	switch name := ctx.Param("name"); name {
+	case "cmdline":
+		pprof.Cmdline(ctx.Writer, ctx.Request)
+	case "profile":
+		pprof.Profile(ctx.Writer, ctx.Request)
+	case "symbol":
+		pprof.Symbol(ctx.Writer, ctx.Request)
+	case "trace":
+		pprof.Trace(ctx.Writer, ctx.Request)
+	default:
+		pprof.Handler(name).ServeHTTP(ctx.Writer, ctx.Request)
+	}
+}
@@ -0,0 +1,131 @@
+package admin
+
+import (
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/calummacc/goblin/internal/core"
+)
+
+type RuntimeStats struct {
+	Goroutines  int           `json:"goroutines"`
+	HeapAlloc   uint64        `json:"heap_alloc"`
+	HeapObjects uint64        `json:"heap_objects"`
+	NumGC       uint32        `json:"num_gc"`
+	LastGCPause time.Duration `json:"last_gc_pause"`
+	CollectedAt time.Time     `json:"collected_at"`
+}
+
+// Thresholds above which the collector logs a warning. Zero disables a check.
+type Thresholds struct {
+	Goroutines int
+	HeapAlloc  uint64
+	GCPause    time.Duration
+}
+
+// RuntimeCollector periodically samples goroutine, heap and GC statistics
+type RuntimeCollector struct {
+	interval   time.Duration
+	thresholds Thresholds
+
+	logger *core.Logger // set by AdminModule
+
+	mu    sync.RWMutex
+	stats RuntimeStats
+	stop  chan struct{}
+	done  chan struct{}
+}
+
+func NewRuntimeCollector(interval time.Duration, thresholds Thresholds) *RuntimeCollector {
+	return &RuntimeCollector{
+		interval:   interval,
+		thresholds: thresholds,
+	}
+}
+
+func (r *RuntimeCollector) Start() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.stop != nil || r.interval <= 0 {
+		return
+	}
+	r.stop = make(chan struct{})
+	r.done = make(chan struct{})
+
+	go r.run(r.stop, r.done)
+}
+
+func (r *RuntimeCollector) Stop() {
+	r.mu.Lock()
+	stop, done := r.stop, r.done
+	r.stop, r.done = nil, nil
+	r.mu.Unlock()
+
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+}
+
+// Stats returns the latest sample
+func (r *RuntimeCollector) Stats() RuntimeStats {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.stats
+}
+
+func (r *RuntimeCollector) run(stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	r.collect()
+	for {
+		select {
+		case <-ticker.C:
+			r.collect()
+		case <-stop:
+			return
+		}
+	}
+}
+
+func (r *RuntimeCollector) collect() {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	stats := RuntimeStats{
+		Goroutines:  runtime.NumGoroutine(),
+		HeapAlloc:   mem.HeapAlloc,
+		HeapObjects: mem.HeapObjects,
+		NumGC:       mem.NumGC,
+		CollectedAt: time.Now(),
+	}
+	if mem.NumGC > 0 {
+		stats.LastGCPause = time.Duration(mem.PauseNs[(mem.NumGC+255)%256])
+	}
+
+	r.mu.Lock()
+	r.stats = stats
+	r.mu.Unlock()
+
+	if limit := r.thresholds.Goroutines; limit > 0 && stats.Goroutines > limit {
+		r.log().Warnf("WARN: %d goroutines running (threshold %d)", stats.Goroutines, limit)
+	}
+	if limit := r.thresholds.HeapAlloc; limit > 0 && stats.HeapAlloc > limit {
+		r.log().Warnf("WARN: heap at %d bytes (threshold %d)", stats.HeapAlloc, limit)
+	}
+	if limit := r.thresholds.GCPause; limit > 0 && stats.LastGCPause > limit {
+		r.log().Warnf("WARN: GC pause of %v (threshold %v)", stats.LastGCPause, limit)
+	}
+}
+
+func (r *RuntimeCollector) log() *core.Logger {
+	if r.logger == nil {
+		return core.NewLogger(core.LevelInfo)
+	}
+	return r.logger
+}
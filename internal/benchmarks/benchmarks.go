@@ -0,0 +1,217 @@
+// Package benchmarks measures the framework's hot paths, so changes can be
+// compared against a recorded baseline. Run them with cmd/benchmarks.
+//
+// The suite covers route dispatch, dependency resolution, the response
+// middleware pipeline and validation. Goblin has no pipes or event bus,
+// and its container has no scopes, so those have nothing to measure.
+package benchmarks
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/calummacc/goblin/internal/core"
+	"github.com/calummacc/goblin/internal/middleware"
+	"github.com/calummacc/goblin/internal/validation"
+	"github.com/gin-gonic/gin"
+)
+
+// Benchmark is one measured operation
+type Benchmark struct {
+	Name string
+	Func func(b *testing.B)
+}
+
+// Result is the outcome of a Benchmark, as stored in baseline files
+type Result struct {
+	Name        string `json:"name"`
+	NsPerOp     int64  `json:"ns_per_op"`
+	AllocsPerOp int64  `json:"allocs_per_op"`
+	BytesPerOp  int64  `json:"bytes_per_op"`
+}
+
+// Suite lists every benchmark, in report order
+var Suite = []Benchmark{
+	{"RouteDispatch/static", routeDispatch("/users")},
+	{"RouteDispatch/param", routeDispatch("/users/42")},
+	{"RouteDispatch/notfound", routeDispatch("/missing")},
+	{"Resolve", resolve},
+	{"Group", group},
+	{"Provide", provide},
+	{"Pipeline/plain", pipeline()},
+	{"Pipeline/serializer+envelope+fields", pipeline(
+		middleware.Serializer(middleware.SerializerOptions{Naming: middleware.NamingCamelCase}),
+		middleware.Envelope(),
+		middleware.Fields(),
+	)},
+	{"Validation/valid", validate(validUser)},
+	{"Validation/invalid", validate(userRequest{Name: "x", Email: "not an email"})},
+}
+
+// Run runs the benchmarks whose name matches filter, all when it is empty,
+// count times each, keeping the fastest run to damp noise
+func Run(filter string, count int) ([]Result, error) {
+	match, err := regexp.Compile(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// gin.Default logs every request
+	writer := gin.DefaultWriter
+	gin.DefaultWriter = io.Discard
+	defer func() { gin.DefaultWriter = writer }()
+
+	results := make([]Result, 0, len(Suite))
+	for _, benchmark := range Suite {
+		if !match.MatchString(benchmark.Name) {
+			continue
+		}
+		var fastest Result
+		for i := 0; i < count || i == 0; i++ {
+			result := testing.Benchmark(benchmark.Func)
+			if i == 0 || result.NsPerOp() < fastest.NsPerOp {
+				fastest = Result{
+					Name:        benchmark.Name,
+					NsPerOp:     result.NsPerOp(),
+					AllocsPerOp: result.AllocsPerOp(),
+					BytesPerOp:  result.AllocedBytesPerOp(),
+				}
+			}
+		}
+		results = append(results, fastest)
+	}
+	return results, nil
+}
+
+type benchModule struct {
+	core.BaseModule
+	handlers []gin.HandlerFunc
+}
+
+func (m *benchModule) Middleware() []gin.HandlerFunc {
+	return m.handlers
+}
+
+func (m *benchModule) RegisterRoutes(router *gin.RouterGroup) {
+	router.GET("/users", func(c *gin.Context) {
+		c.JSON(http.StatusOK, []gin.H{{"user_id": 1, "user_name": "ada"}})
+	})
+	router.GET("/users/:id", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"user_id": c.Param("id"), "user_name": "ada"})
+	})
+	for i := 0; i < 50; i++ {
+		router.GET(fmt.Sprintf("/resource%d/:id", i), func(c *gin.Context) {
+			c.Status(http.StatusNoContent)
+		})
+	}
+}
+
+// handler compiles an application with a route table of realistic size
+func handler(b *testing.B, handlers ...gin.HandlerFunc) http.Handler {
+	module := core.NewTestingModule(&benchModule{handlers: handlers})
+	app, err := module.Compile()
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(func() { module.Close() })
+
+	h, err := app.Handler()
+	if err != nil {
+		b.Fatal(err)
+	}
+	return h
+}
+
+func serve(b *testing.B, h http.Handler, path string) {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		h.ServeHTTP(httptest.NewRecorder(), req)
+	}
+}
+
+func routeDispatch(path string) func(b *testing.B) {
+	return func(b *testing.B) {
+		serve(b, handler(b), path)
+	}
+}
+
+func pipeline(handlers ...gin.HandlerFunc) func(b *testing.B) {
+	return func(b *testing.B) {
+		serve(b, handler(b, handlers...), "/users/42?fields=userId")
+	}
+}
+
+type repository interface{ Find(id int) string }
+
+type memoryRepository struct{}
+
+func (memoryRepository) Find(int) string { return "" }
+
+func resolve(b *testing.B) {
+	container := core.NewContainer()
+	core.Bind[repository](container, memoryRepository{})
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := core.Resolve[repository](container); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func group(b *testing.B) {
+	container := core.NewContainer()
+	for i := 0; i < 10; i++ {
+		core.Contribute[repository](container, "repositories", memoryRepository{})
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := core.Group[repository](container, "repositories"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func provide(b *testing.B) {
+	container := core.NewContainer()
+	core.Bind[repository](container, memoryRepository{})
+	constructor := func(repo repository) (*service, error) { return &service{repo: repo}, nil }
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := container.Provide(constructor); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+type service struct {
+	repo repository
+}
+
+type userRequest struct {
+	Name  string `json:"name" binding:"required,min=2"`
+	Email string `json:"email" binding:"required,email"`
+	Age   int    `json:"age" binding:"gte=0,lte=150"`
+}
+
+var validUser = userRequest{Name: "ada", Email: "ada@example.com", Age: 36}
+
+func validate(user userRequest) func(b *testing.B) {
+	return func(b *testing.B) {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodPost, "/", nil)
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			validation.Validate(c, &user)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package benchmarks
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+)
+
+// Regression is a benchmark slower than its baseline beyond the tolerance
+type Regression struct {
+	Name     string
+	Baseline Result
+	Current  Result
+	Change   float64 // Relative change in ns/op, e.g. 0.25 for 25% slower
+}
+
+// Compare returns the benchmarks in current that are slower than in
+// baseline by more than tolerance, e.g. 0.1 for 10%, or that allocate
+// more. A negative tolerance only compares allocations. Benchmarks missing
+// from either side are skipped.
+func Compare(baseline, current []Result, tolerance float64) []Regression {
+	byName := make(map[string]Result, len(baseline))
+	for _, result := range baseline {
+		byName[result.Name] = result
+	}
+
+	regressions := make([]Regression, 0)
+	for _, result := range current {
+		base, found := byName[result.Name]
+		if !found || base.NsPerOp == 0 {
+			continue
+		}
+		change := float64(result.NsPerOp-base.NsPerOp) / float64(base.NsPerOp)
+		if (tolerance >= 0 && change > tolerance) || result.AllocsPerOp > base.AllocsPerOp {
+			regressions = append(regressions, Regression{Name: result.Name, Baseline: base, Current: result, Change: change})
+		}
+	}
+	return regressions
+}
+
+// ReadBaseline reads results written by WriteBaseline
+func ReadBaseline(path string) ([]Result, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var results []Result
+	if err := json.Unmarshal(data, &results); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return results, nil
+}
+
+func WriteBaseline(path string, results []Result) error {
+	data, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// Markdown writes results as a table for the README, with the change
+// against baseline when one is given
+func Markdown(w io.Writer, results, baseline []Result) {
+	byName := make(map[string]Result, len(baseline))
+	for _, result := range baseline {
+		byName[result.Name] = result
+	}
+
+	fmt.Fprintln(w, "| Benchmark | ns/op | allocs/op | B/op | vs baseline |")
+	fmt.Fprintln(w, "|---|---:|---:|---:|---:|")
+	for _, result := range results {
+		change := ""
+		if base, found := byName[result.Name]; found && base.NsPerOp > 0 {
+			change = fmt.Sprintf("%+.1f%%", 100*float64(result.NsPerOp-base.NsPerOp)/float64(base.NsPerOp))
+		}
+		fmt.Fprintf(w, "| %s | %d | %d | %d | %s |\n",
+			result.Name, result.NsPerOp, result.AllocsPerOp, result.BytesPerOp, change)
+	}
+}
+
+// Text writes regressions as an aligned table
+func Text(w io.Writer, regressions []Regression) {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "BENCHMARK\tBASELINE\tCURRENT\tCHANGE")
+	for _, regression := range regressions {
+		fmt.Fprintf(tw, "%s\t%d ns/op, %d allocs\t%d ns/op, %d allocs\t%+.1f%%\n",
+			regression.Name,
+			regression.Baseline.NsPerOp, regression.Baseline.AllocsPerOp,
+			regression.Current.NsPerOp, regression.Current.AllocsPerOp,
+			100*regression.Change)
+	}
+	tw.Flush()
+}
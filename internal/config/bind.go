@@ -0,0 +1,193 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+var (
+	ErrInvalidTarget = errors.New("config: target must be a pointer to a struct")
+	durationType     = reflect.TypeOf(time.Duration(0))
+)
+
+// KeyError is a configuration key Bind could not use
+type KeyError struct {
+	Key string
+	Err error
+}
+
+// BindError lists every missing or invalid key found by Bind
+type BindError struct {
+	Prefix string
+	Keys   []KeyError
+}
+
+func (e *BindError) Error() string {
+	parts := make([]string, 0, len(e.Keys))
+	for _, key := range e.Keys {
+		parts = append(parts, fmt.Sprintf("%s: %v", key.Key, key.Err))
+	}
+	return fmt.Sprintf("config: %s: %s", e.Prefix, strings.Join(parts, "; "))
+}
+
+// Bind fills the struct target points to from the keys under prefix, then
+// validates it with its `binding` rules. Fields are read from the key in
+// their `config` tag, or their lower-cased name, and fall back to their
+// `default` tag; nested structs read the keys under their own name:
+//
+//	type DatabaseConfig struct {
+//		Host    string        `config:"host" binding:"required"`
+//		Port    int           `config:"port" default:"5432"`
+//		Timeout time.Duration `config:"timeout" default:"5s"`
+//	}
+//	err := cfg.Bind("database", &db)
+//
+// All failures are reported together in a *BindError, by config key, e.g.
+// "database.host: missing".
+func (s *ConfigService) Bind(prefix string, target interface{}) error {
+	value := reflect.ValueOf(target)
+	if value.Kind() != reflect.Pointer || value.Elem().Kind() != reflect.Struct {
+		return ErrInvalidTarget
+	}
+
+	bindErr := &BindError{Prefix: prefix}
+	// Validation reports fields by namespace, e.g. DatabaseConfig.Host
+	keys := make(map[string]string)
+	s.bindStruct(prefix, value.Elem().Type().Name(), value.Elem(), keys, bindErr)
+
+	if binding.Validator != nil {
+		reported := make(map[string]bool, len(bindErr.Keys))
+		for _, keyErr := range bindErr.Keys {
+			reported[keyErr.Key] = true
+		}
+
+		err := binding.Validator.ValidateStruct(target)
+		var fieldErrs validator.ValidationErrors
+		switch {
+		case errors.As(err, &fieldErrs):
+			for _, fieldErr := range fieldErrs {
+				key, found := keys[fieldErr.StructNamespace()]
+				if !found {
+					key = fieldErr.StructNamespace()
+				}
+				if reported[key] {
+					// Its value did not parse, which says more
+					continue
+				}
+				bindErr.Keys = append(bindErr.Keys, KeyError{Key: key, Err: ruleError(fieldErr)})
+			}
+		case err != nil:
+			return fmt.Errorf("config: %s: %w", prefix, err)
+		}
+	}
+
+	if len(bindErr.Keys) > 0 {
+		return bindErr
+	}
+	return nil
+}
+
+func ruleError(fieldErr validator.FieldError) error {
+	if fieldErr.Tag() == "required" {
+		return errors.New("missing")
+	}
+	if fieldErr.Param() != "" {
+		return fmt.Errorf("invalid, must satisfy %s=%s", fieldErr.Tag(), fieldErr.Param())
+	}
+	return fmt.Errorf("invalid, must satisfy %s", fieldErr.Tag())
+}
+
+func (s *ConfigService) bindStruct(prefix, namespace string, value reflect.Value, keys map[string]string, bindErr *BindError) {
+	structType := value.Type()
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := field.Tag.Get("config")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		key := join(prefix, name)
+		fieldNamespace := namespace + "." + field.Name
+		keys[fieldNamespace] = key
+
+		if field.Type.Kind() == reflect.Struct {
+			s.bindStruct(key, fieldNamespace, value.Field(i), keys, bindErr)
+			continue
+		}
+
+		raw, found := s.Get(key)
+		if !found {
+			if raw, found = field.Tag.Lookup("default"); !found {
+				continue
+			}
+		}
+		if err := setField(value.Field(i), raw); err != nil {
+			bindErr.Keys = append(bindErr.Keys, KeyError{Key: key, Err: err})
+		}
+	}
+}
+
+func setField(field reflect.Value, raw string) error {
+	raw = strings.TrimSpace(raw)
+	if field.Type() == durationType {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(d))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(raw, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(raw, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	case reflect.Slice:
+		items := splitList(raw)
+		slice := reflect.MakeSlice(field.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := setField(slice.Index(i), item); err != nil {
+				return err
+			}
+		}
+		field.Set(slice)
+	default:
+		return fmt.Errorf("unsupported field type %s", field.Type())
+	}
+	return nil
+}
@@ -0,0 +1,379 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+type Options struct {
+	Files     []string // YAML or JSON files, later ones override earlier ones
+	EnvFiles  []string // .env files, skipped when missing
+	EnvPrefix string   // Prefix of environment variables, e.g. "APP_"
+	IgnoreEnv bool     // Do not read the process environment
+	Profile   string   // Also read each file's profile variant, e.g. config.prod.yaml
+
+	WatchInterval  time.Duration // How often ConfigModule polls the files for changes, 0 disables
+	ReloadOnSignal bool          // Reload on SIGHUP while ConfigModule runs
+}
+
+var defaultOptions = Options{
+	EnvFiles: []string{".env"},
+}
+
+// WithFile adds a YAML (.yaml, .yml) or JSON (.json) file. Unlike .env
+// files it must exist.
+func WithFile(path string) func(*Options) {
+	return func(opts *Options) {
+		opts.Files = append(opts.Files, path)
+	}
+}
+
+// WithEnvFiles replaces the .env files read, by default ".env"
+func WithEnvFiles(paths ...string) func(*Options) {
+	return func(opts *Options) {
+		opts.EnvFiles = paths
+	}
+}
+
+func WithEnvPrefix(prefix string) func(*Options) {
+	return func(opts *Options) {
+		opts.EnvPrefix = prefix
+	}
+}
+
+// WithProfile layers profile-specific files over the base ones: with
+// profile "prod", config.prod.yaml overrides config.yaml and .env.prod
+// overrides .env. Profile files are skipped when missing.
+func WithProfile(profile string) func(*Options) {
+	return func(opts *Options) {
+		opts.Profile = profile
+	}
+}
+
+// WithWatch makes ConfigModule reload the configuration when one of its
+// files changes, checking every interval
+func WithWatch(interval time.Duration) func(*Options) {
+	return func(opts *Options) {
+		opts.WatchInterval = interval
+	}
+}
+
+// WithReloadOnSignal makes ConfigModule reload the configuration on SIGHUP
+func WithReloadOnSignal() func(*Options) {
+	return func(opts *Options) {
+		opts.ReloadOnSignal = true
+	}
+}
+
+// WithoutEnv stops the process environment from overriding configuration,
+// mostly for tests
+func WithoutEnv() func(*Options) {
+	return func(opts *Options) {
+		opts.IgnoreEnv = true
+	}
+}
+
+// ConfigService holds configuration under dotted keys such as
+// "database.host". Environment variables override .env files, which
+// override YAML and JSON files. The environment is looked up by the key's
+// variable name: "database.host" is DATABASE_HOST, after the prefix.
+//
+// Reload swaps in freshly read files atomically; the process environment
+// is read live and is not part of a reload.
+type ConfigService struct {
+	options Options
+	values  atomic.Pointer[sources]
+	lookup  func(string) (string, bool)
+
+	reloadMu    sync.Mutex // serializes Reload
+	mu          sync.Mutex // guards validators and subscribers
+	validators  []func(*ConfigService) error
+	subscribers map[int]func(ChangeEvent)
+	nextID      int
+}
+
+// sources holds the values read from files, swapped as a whole on reload
+type sources struct {
+	files  map[string]string
+	dotenv map[string]string
+}
+
+// Load reads the configuration sources
+func Load(opts ...func(*Options)) (*ConfigService, error) {
+	options := defaultOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	config := &ConfigService{
+		options: options,
+		lookup:  os.LookupEnv,
+	}
+	if options.IgnoreEnv {
+		config.lookup = func(string) (string, bool) { return "", false }
+	}
+
+	values, err := readSources(options)
+	if err != nil {
+		return nil, err
+	}
+	config.values.Store(values)
+	return config, nil
+}
+
+// readSources reads the files and .env files of options
+func readSources(options Options) (*sources, error) {
+	values := &sources{
+		files:  make(map[string]string),
+		dotenv: make(map[string]string),
+	}
+	for _, path := range options.Files {
+		if err := values.loadFile(path); err != nil {
+			return nil, err
+		}
+		if options.Profile == "" {
+			continue
+		}
+		profilePath := profileFile(path, options.Profile)
+		if _, err := os.Stat(profilePath); err == nil {
+			if err := values.loadFile(profilePath); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, path := range options.EnvFiles {
+		if err := values.loadEnvFile(path); err != nil {
+			return nil, err
+		}
+		if options.Profile != "" {
+			if err := values.loadEnvFile(path + "." + options.Profile); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return values, nil
+}
+
+// profileFile returns the profile variant of path, e.g. config.prod.yaml
+func profileFile(path, profile string) string {
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "." + profile + ext
+}
+
+// Profile returns the profile the configuration was loaded for
+func (s *ConfigService) Profile() string {
+	return s.options.Profile
+}
+
+func (s *sources) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+
+	var values map[string]interface{}
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &values)
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		err = decoder.Decode(&values)
+	default:
+		return fmt.Errorf("config: %s: unsupported file type %q", path, ext)
+	}
+	if err != nil {
+		return fmt.Errorf("config: %s: %w", path, err)
+	}
+
+	flatten("", values, s.files)
+	return nil
+}
+
+// flatten stores nested values under dotted keys. Lists are joined with
+// commas, the form Strings and Bind read them back in.
+func flatten(prefix string, value interface{}, out map[string]string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			flatten(join(prefix, strings.ToLower(key)), child, out)
+		}
+	case []interface{}:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			items = append(items, fmt.Sprint(item))
+		}
+		out[prefix] = strings.Join(items, ",")
+	case nil:
+		out[prefix] = ""
+	default:
+		out[prefix] = fmt.Sprint(v)
+	}
+}
+
+func join(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+func (s *sources) loadEnvFile(path string) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		text = strings.TrimPrefix(text, "export ")
+
+		name, value, found := strings.Cut(text, "=")
+		if !found {
+			return fmt.Errorf("config: %s:%d: expected NAME=value", path, line)
+		}
+		value = strings.TrimSpace(value)
+		if unquoted, err := strconv.Unquote(value); err == nil && strings.HasPrefix(value, `"`) {
+			value = unquoted
+		} else if len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'' {
+			value = value[1 : len(value)-1]
+		} else if i := strings.Index(value, " #"); i >= 0 {
+			value = strings.TrimSpace(value[:i])
+		}
+		s.dotenv[strings.TrimSpace(name)] = value
+	}
+	return scanner.Err()
+}
+
+// EnvName returns the environment variable that overrides key
+func (s *ConfigService) EnvName(key string) string {
+	return s.options.EnvPrefix + strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(key))
+}
+
+// Get returns the value of key and whether it is set anywhere
+func (s *ConfigService) Get(key string) (string, bool) {
+	return s.get(s.values.Load(), key)
+}
+
+func (s *ConfigService) get(values *sources, key string) (string, bool) {
+	name := s.EnvName(key)
+	if value, found := s.lookup(name); found {
+		return value, true
+	}
+	if value, found := values.dotenv[name]; found {
+		return value, true
+	}
+	value, found := values.files[strings.ToLower(key)]
+	return value, found
+}
+
+func (s *ConfigService) String(key, fallback string) string {
+	if value, found := s.Get(key); found {
+		return value
+	}
+	return fallback
+}
+
+// Strings reads a comma-separated value or a list from a file
+func (s *ConfigService) Strings(key string, fallback []string) []string {
+	value, found := s.Get(key)
+	if !found {
+		return fallback
+	}
+	return splitList(value)
+}
+
+// Int returns fallback when key is unset and an error when it is not an
+// integer, so typos in configuration are not silently ignored
+func (s *ConfigService) Int(key string, fallback int) (int, error) {
+	value, found := s.Get(key)
+	if !found {
+		return fallback, nil
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return fallback, fmt.Errorf("config: %s: %w", key, err)
+	}
+	return n, nil
+}
+
+func (s *ConfigService) Float(key string, fallback float64) (float64, error) {
+	value, found := s.Get(key)
+	if !found {
+		return fallback, nil
+	}
+	f, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return fallback, fmt.Errorf("config: %s: %w", key, err)
+	}
+	return f, nil
+}
+
+func (s *ConfigService) Bool(key string, fallback bool) (bool, error) {
+	value, found := s.Get(key)
+	if !found {
+		return fallback, nil
+	}
+	b, err := strconv.ParseBool(strings.TrimSpace(value))
+	if err != nil {
+		return fallback, fmt.Errorf("config: %s: %w", key, err)
+	}
+	return b, nil
+}
+
+// Duration reads values such as "30s" or "1h30m"
+func (s *ConfigService) Duration(key string, fallback time.Duration) (time.Duration, error) {
+	value, found := s.Get(key)
+	if !found {
+		return fallback, nil
+	}
+	d, err := time.ParseDuration(strings.TrimSpace(value))
+	if err != nil {
+		return fallback, fmt.Errorf("config: %s: %w", key, err)
+	}
+	return d, nil
+}
+
+// Keys lists the keys set in files. Keys only set in the environment are
+// not listed since variable names cannot be mapped back reliably.
+func (s *ConfigService) Keys() []string {
+	files := s.values.Load().files
+	keys := make([]string, 0, len(files))
+	for key := range files {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func splitList(value string) []string {
+	if strings.TrimSpace(value) == "" {
+		return []string{}
+	}
+	items := strings.Split(value, ",")
+	for i, item := range items {
+		items[i] = strings.TrimSpace(item)
+	}
+	return items
+}
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"context"
+	"strings"
+
+	"github.com/calummacc/goblin/internal/core"
+	"go.uber.org/fx"
+)
+
+// ConfigModule makes a ConfigService available to other modules, through
+// the container in Configure and through fx for constructors. Add it
+// before the modules that read configuration. With WithWatch or
+// WithReloadOnSignal it reloads the configuration while the application
+// runs; see ConfigService.OnChange.
+type ConfigModule struct {
+	core.BaseModule
+	config *ConfigService
+	err    error
+	stop   context.CancelFunc
+}
+
+// ForRoot loads the configuration right away, so it can also be read while
+// assembling the application. A loading error fails startup; until then
+// the configuration is empty.
+func ForRoot(opts ...func(*Options)) *ConfigModule {
+	config, err := Load(opts...)
+	if err != nil {
+		config, _ = Load(WithEnvFiles(), WithoutEnv())
+	}
+	return &ConfigModule{config: config, err: err}
+}
+
+func (m *ConfigModule) Config() *ConfigService {
+	return m.config
+}
+
+// Err returns the error loading the configuration
+func (m *ConfigModule) Err() error {
+	return m.err
+}
+
+func (m *ConfigModule) Configure(container *core.Container) {
+	core.Bind(container, m.config)
+}
+
+func (m *ConfigModule) ProvideDependencies() fx.Option {
+	return fx.Provide(func() (*ConfigService, error) {
+		return m.config, m.err
+	})
+}
+
+func (m *ConfigModule) OnInit() error {
+	if m.err != nil {
+		return m.err
+	}
+
+	ctx, stop := context.WithCancel(context.Background())
+	m.stop = stop
+	if interval := m.config.options.WatchInterval; interval > 0 {
+		go m.config.Watch(ctx, interval)
+	}
+	if m.config.options.ReloadOnSignal {
+		go m.config.WatchSignal(ctx)
+	}
+	return nil
+}
+
+func (m *ConfigModule) OnDestroy() error {
+	if m.stop != nil {
+		m.stop()
+	}
+	return nil
+}
+
+// ConditionalOnConfig holds when key is set to value in cfg, ignoring
+// case, so a module can be picked by configuration instead of code:
+//
+//	func (m *RedisCacheModule) Condition() core.Condition {
+//		return config.ConditionalOnConfig(m.config, "cache.driver", "redis")
+//	}
+func ConditionalOnConfig(cfg *ConfigService, key, value string) core.Condition {
+	return func(core.ApplicationOptions) bool {
+		current, found := cfg.Get(key)
+		return found && strings.EqualFold(strings.TrimSpace(current), value)
+	}
+}
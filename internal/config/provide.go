@@ -0,0 +1,61 @@
+package config
+
+import (
+	"github.com/calummacc/goblin/internal/core"
+	"go.uber.org/fx"
+)
+
+// StructModule provides a configuration struct bound with Bind to other
+// modules, through the container and fx:
+//
+//	app.AddModule(config.ProvideStruct[DatabaseConfig](cfg, "database"))
+//
+//	func (m *StoreModule) Configure(container *core.Container) {
+//		db := core.MustResolve[*DatabaseConfig](container)
+//	}
+//
+// Missing or invalid keys fail startup with the BindError, and make a later
+// Reload keep the current configuration. The struct itself is bound once;
+// use ConfigService.OnChange to react to reloaded values.
+type StructModule[T any] struct {
+	core.BaseModule
+	value *T
+	err   error
+}
+
+// ProvideStruct binds the keys under prefix into a new T right away, like
+// ForRoot, so the struct can also be read while assembling the application
+func ProvideStruct[T any](cfg *ConfigService, prefix string) *StructModule[T] {
+	value := new(T)
+	cfg.ValidateReload(func(next *ConfigService) error {
+		return next.Bind(prefix, new(T))
+	})
+	return &StructModule[T]{value: value, err: cfg.Bind(prefix, value)}
+}
+
+func (m *StructModule[T]) Value() *T {
+	return m.value
+}
+
+// Err returns the error binding the struct
+func (m *StructModule[T]) Err() error {
+	return m.err
+}
+
+func (m *StructModule[T]) Configure(container *core.Container) {
+	core.Bind(container, m.value)
+}
+
+func (m *StructModule[T]) ProvideDependencies() fx.Option {
+	return fx.Provide(func() (*T, error) {
+		return m.value, m.err
+	})
+}
+
+func (m *StructModule[T]) OnInit() error {
+	return m.err
+}
+
+func (m *StructModule[T]) OnDestroy() error {
+	return nil
+}
@@ -0,0 +1,232 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// EventChanged names the event published after a reload changes values
+const EventChanged = "config.changed"
+
+// Change is a key whose value differs after a reload. Old or New is empty
+// when the key was unset.
+type Change struct {
+	Key string `json:"key"`
+	Old string `json:"old"`
+	New string `json:"new"`
+}
+
+// ChangeEvent is published to OnChange subscribers after a reload
+type ChangeEvent struct {
+	Event   string   `json:"event"`
+	Changes []Change `json:"changes"`
+}
+
+// Has reports whether key or a key under it changed, e.g. "database" for
+// "database.host"
+func (e ChangeEvent) Has(key string) bool {
+	key = strings.ToLower(key)
+	for _, change := range e.Changes {
+		if change.Key == key || strings.HasPrefix(change.Key, key+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// OnChange calls handler after every reload that changes values, until
+// the returned function is called. Handlers run one at a time on the
+// goroutine that reloaded and must not call Reload themselves.
+//
+//	cfg.OnChange(func(event config.ChangeEvent) {
+//		if event.Has("log.level") {
+//			logger.SetLevel(cfg.String("log.level", "info"))
+//		}
+//	})
+func (s *ConfigService) OnChange(handler func(ChangeEvent)) (unsubscribe func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.subscribers == nil {
+		s.subscribers = make(map[int]func(ChangeEvent))
+	}
+	id := s.nextID
+	s.nextID++
+	s.subscribers[id] = handler
+
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(s.subscribers, id)
+	}
+}
+
+// ValidateReload adds a check a reloaded configuration must pass before
+// it replaces the current one, e.g. that a struct still binds
+func (s *ConfigService) ValidateReload(check func(next *ConfigService) error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.validators = append(s.validators, check)
+}
+
+// Reload reads the files again and swaps them in when they load and pass
+// every ValidateReload check; otherwise the current values stay. When
+// values changed, subscribers receive a ChangeEvent.
+func (s *ConfigService) Reload() error {
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+
+	next, err := readSources(s.options)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	validators := append([]func(*ConfigService) error(nil), s.validators...)
+	s.mu.Unlock()
+
+	candidate := &ConfigService{options: s.options, lookup: s.lookup}
+	candidate.values.Store(next)
+	for _, check := range validators {
+		if err := check(candidate); err != nil {
+			return fmt.Errorf("config: reload rejected: %w", err)
+		}
+	}
+
+	previous := s.values.Swap(next)
+	changes := s.diff(previous, next)
+	if len(changes) == 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	ids := make([]int, 0, len(s.subscribers))
+	for id := range s.subscribers {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	handlers := make([]func(ChangeEvent), 0, len(ids))
+	for _, id := range ids {
+		handlers = append(handlers, s.subscribers[id])
+	}
+	s.mu.Unlock()
+
+	event := ChangeEvent{Event: EventChanged, Changes: changes}
+	for _, handler := range handlers {
+		handler(event)
+	}
+	return nil
+}
+
+// diff compares the values of every key either side sets. Keys set in
+// .env files are named after their variable, e.g. DATABASE_HOST becomes
+// "database.host".
+func (s *ConfigService) diff(previous, next *sources) []Change {
+	keys := make(map[string]bool)
+	for _, values := range []*sources{previous, next} {
+		for key := range values.files {
+			keys[key] = true
+		}
+		for name := range values.dotenv {
+			if key, ok := s.keyName(name); ok {
+				keys[key] = true
+			}
+		}
+	}
+
+	changes := make([]Change, 0)
+	for key := range keys {
+		old, _ := s.get(previous, key)
+		current, _ := s.get(next, key)
+		if old != current {
+			changes = append(changes, Change{Key: key, Old: old, New: current})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Key < changes[j].Key })
+	return changes
+}
+
+// keyName guesses the key a variable overrides, the reverse of EnvName
+func (s *ConfigService) keyName(name string) (string, bool) {
+	if !strings.HasPrefix(name, s.options.EnvPrefix) {
+		return "", false
+	}
+	return strings.ToLower(strings.ReplaceAll(strings.TrimPrefix(name, s.options.EnvPrefix), "_", ".")), true
+}
+
+// Watch reloads the configuration whenever one of its files changes,
+// checking every interval until ctx ends. Failed reloads are logged and
+// the current values stay.
+func (s *ConfigService) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	stamps := s.stamps()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			current := s.stamps()
+			if current == stamps {
+				continue
+			}
+			stamps = current
+			if err := s.Reload(); err != nil {
+				log.Printf("Config reload failed: %v", err)
+			}
+		}
+	}
+}
+
+// WatchSignal reloads the configuration on every SIGHUP until ctx ends
+func (s *ConfigService) WatchSignal(ctx context.Context) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	defer signal.Stop(signals)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-signals:
+			if err := s.Reload(); err != nil {
+				log.Printf("Config reload failed: %v", err)
+			}
+		}
+	}
+}
+
+// stamps summarizes the size and modification time of every file the
+// configuration may be read from, missing ones included
+func (s *ConfigService) stamps() string {
+	paths := make([]string, 0, 2*(len(s.options.Files)+len(s.options.EnvFiles)))
+	for _, path := range s.options.Files {
+		paths = append(paths, path)
+		if s.options.Profile != "" {
+			paths = append(paths, profileFile(path, s.options.Profile))
+		}
+	}
+	for _, path := range s.options.EnvFiles {
+		paths = append(paths, path)
+		if s.options.Profile != "" {
+			paths = append(paths, path+"."+s.options.Profile)
+		}
+	}
+
+	var b strings.Builder
+	for _, path := range paths {
+		if info, err := os.Stat(path); err == nil {
+			fmt.Fprintf(&b, "%s:%d:%d;", path, info.Size(), info.ModTime().UnixNano())
+		} else {
+			fmt.Fprintf(&b, "%s:-;", path)
+		}
+	}
+	return b.String()
+}
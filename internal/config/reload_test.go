@@ -0,0 +1,131 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReloadPublishesChanges(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "config.yaml")
+	envFile := filepath.Join(dir, ".env")
+	writeFile(t, file, "log:\n  level: info\nport: 3000\nold: gone\n")
+	writeFile(t, envFile, "APP_CACHE_TTL=30s\n")
+
+	cfg, err := Load(WithFile(file), WithEnvFiles(envFile), WithEnvPrefix("APP_"), WithoutEnv())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var events []ChangeEvent
+	cfg.OnChange(func(event ChangeEvent) { events = append(events, event) })
+
+	writeFile(t, file, "log:\n  level: debug\nport: 3000\nnew: here\n")
+	writeFile(t, envFile, "APP_CACHE_TTL=1m\n")
+	if err := cfg.Reload(); err != nil {
+		t.Fatalf("Reload() = %v", err)
+	}
+
+	if got := cfg.String("log.level", ""); got != "debug" {
+		t.Errorf("log.level = %q, want debug", got)
+	}
+	if len(events) != 1 {
+		t.Fatalf("got %d events, want 1", len(events))
+	}
+	want := []Change{
+		{Key: "cache.ttl", Old: "30s", New: "1m"},
+		{Key: "log.level", Old: "info", New: "debug"},
+		{Key: "new", Old: "", New: "here"},
+		{Key: "old", Old: "gone", New: ""},
+	}
+	if events[0].Event != EventChanged || !reflect.DeepEqual(events[0].Changes, want) {
+		t.Errorf("event = %+v, want changes %+v", events[0], want)
+	}
+	if !events[0].Has("log") || events[0].Has("port") {
+		t.Errorf("Has reports the wrong keys for %+v", events[0].Changes)
+	}
+
+	// Reloading unchanged files publishes nothing
+	if err := cfg.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 {
+		t.Errorf("got %d events after an unchanged reload, want 1", len(events))
+	}
+}
+
+func TestReloadKeepsValuesFailingValidation(t *testing.T) {
+	type server struct {
+		Port int `config:"port" binding:"required,min=1"`
+	}
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "config.json")
+	writeFile(t, file, `{"server": {"port": 8080}}`)
+
+	cfg, err := Load(WithFile(file), WithEnvFiles(), WithoutEnv())
+	if err != nil {
+		t.Fatal(err)
+	}
+	module := ProvideStruct[server](cfg, "server")
+	if err := module.Err(); err != nil {
+		t.Fatal(err)
+	}
+	notified := false
+	cfg.OnChange(func(ChangeEvent) { notified = true })
+
+	writeFile(t, file, `{"server": {"port": "eighty"}}`)
+	if err := cfg.Reload(); err == nil {
+		t.Fatal("Reload() = nil, want the bind error")
+	}
+	if got := cfg.String("server.port", ""); got != "8080" || notified {
+		t.Errorf("server.port = %q, notified = %v; want the previous value kept silently", got, notified)
+	}
+
+	writeFile(t, file, `{"server": {"port": `)
+	if err := cfg.Reload(); err == nil {
+		t.Fatal("Reload() = nil, want the parse error")
+	}
+	if got := cfg.String("server.port", ""); got != "8080" {
+		t.Errorf("server.port = %q after a broken file, want 8080", got)
+	}
+}
+
+func TestWatchReloadsChangedFiles(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "config.yaml")
+	writeFile(t, file, "feature: off\n")
+
+	cfg, err := Load(WithFile(file), WithEnvFiles(), WithoutEnv())
+	if err != nil {
+		t.Fatal(err)
+	}
+	changed := make(chan ChangeEvent, 1)
+	cfg.OnChange(func(event ChangeEvent) { changed <- event })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go cfg.Watch(ctx, 10*time.Millisecond)
+
+	time.Sleep(30 * time.Millisecond)
+	writeFile(t, file, "feature: on-for-everyone\n")
+
+	select {
+	case event := <-changed:
+		if !event.Has("feature") {
+			t.Errorf("event = %+v, want a feature change", event)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no change published after the file was rewritten")
+	}
+}
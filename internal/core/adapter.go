@@ -0,0 +1,158 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HTTPAdapter is the seam between the application and the router serving
+// it. Routes and middleware go through it as plain net/http handlers, so
+// modules implementing HTTPRouteModule do not depend on gin. The
+// application ships GinAdapter; another router can be supported by
+// implementing this interface.
+type HTTPAdapter interface {
+	http.Handler
+
+	// Handle registers handler for method and path. Parameters are
+	// written ":name" and catch-alls "*name"; handlers read them with
+	// PathParam.
+	Handle(method, path string, handler http.Handler)
+
+	// Use mounts middleware in front of every route registered later
+	Use(middleware ...func(http.Handler) http.Handler)
+
+	// Routes lists the registered routes
+	Routes() []RouteInfo
+}
+
+// RouteInfo is a route registered on an HTTPAdapter
+type RouteInfo struct {
+	Method string
+	Path   string
+}
+
+// HTTPRouteModule registers its routes through the application's
+// HTTPAdapter rather than a gin router group. A RoutePrefix method, as in
+// PrefixedModule, mounts them under a prefix.
+type HTTPRouteModule interface {
+	Module
+	RegisterHTTPRoutes(adapter HTTPAdapter)
+}
+
+type pathParamsKey struct{}
+
+// WithPathParams returns a context carrying the path parameters of a
+// request, for adapters to hand them to PathParam
+func WithPathParams(ctx context.Context, lookup func(name string) string) context.Context {
+	return context.WithValue(ctx, pathParamsKey{}, lookup)
+}
+
+// PathParam returns the named path parameter of a request served through
+// an HTTPAdapter, or "" when it has none
+func PathParam(r *http.Request, name string) string {
+	if lookup, ok := r.Context().Value(pathParamsKey{}).(func(string) string); ok {
+		return lookup(name)
+	}
+	return ""
+}
+
+// GinAdapter is the HTTPAdapter over a gin engine. Routes registered
+// through it and through gin router groups share one routing table.
+type GinAdapter struct {
+	engine *gin.Engine
+}
+
+func NewGinAdapter(engine *gin.Engine) *GinAdapter {
+	return &GinAdapter{engine: engine}
+}
+
+// Engine returns the gin engine, for code that still needs gin directly
+func (a *GinAdapter) Engine() *gin.Engine {
+	return a.engine
+}
+
+func (a *GinAdapter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.engine.ServeHTTP(w, r)
+}
+
+func (a *GinAdapter) Handle(method, path string, handler http.Handler) {
+	a.engine.Handle(method, path, func(c *gin.Context) {
+		params := c.Params
+		ctx := WithPathParams(c.Request.Context(), params.ByName)
+		handler.ServeHTTP(c.Writer, c.Request.WithContext(ctx))
+	})
+}
+
+// Use runs each middleware as a gin handler. When the middleware does not
+// call the next handler, the chain stops there. A replaced request carries
+// on down the chain; a replaced response writer only does when it still
+// implements gin.ResponseWriter.
+func (a *GinAdapter) Use(middleware ...func(http.Handler) http.Handler) {
+	for _, mw := range middleware {
+		a.engine.Use(ginMiddleware(mw))
+	}
+}
+
+func ginMiddleware(mw func(http.Handler) http.Handler) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		called := false
+		next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+			c.Request = r
+			if writer, ok := w.(gin.ResponseWriter); ok {
+				c.Writer = writer
+			}
+			c.Next()
+		})
+		mw(next).ServeHTTP(c.Writer, c.Request)
+		if !called {
+			c.Abort()
+		}
+	}
+}
+
+func (a *GinAdapter) Routes() []RouteInfo {
+	routes := a.engine.Routes()
+	infos := make([]RouteInfo, 0, len(routes))
+	for _, route := range routes {
+		infos = append(infos, RouteInfo{Method: route.Method, Path: route.Path})
+	}
+	return infos
+}
+
+// prefixedAdapter mounts the routes of an HTTPRouteModule under a prefix
+type prefixedAdapter struct {
+	HTTPAdapter
+	prefix string
+}
+
+func (a prefixedAdapter) Handle(method, path string, handler http.Handler) {
+	a.HTTPAdapter.Handle(method, joinPaths(a.prefix, path), handler)
+}
+
+func joinPaths(prefix, path string) string {
+	if path == "" {
+		return prefix
+	}
+	return strings.TrimSuffix(prefix, "/") + "/" + strings.TrimPrefix(path, "/")
+}
+
+// registerHTTPRoutes lets a module register its routes on adapter, turning
+// router panics into an error like registerModuleRoutes
+func registerHTTPRoutes(module HTTPRouteModule, adapter HTTPAdapter) (reason string) {
+	defer func() {
+		if err := recover(); err != nil {
+			reason = fmt.Sprint(err)
+		}
+	}()
+
+	if prefixed, ok := module.(interface{ RoutePrefix() string }); ok && prefixed.RoutePrefix() != "" {
+		adapter = prefixedAdapter{HTTPAdapter: adapter, prefix: prefixed.RoutePrefix()}
+	}
+	module.RegisterHTTPRoutes(adapter)
+	return ""
+}
@@ -0,0 +1,73 @@
+package core
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type netHTTPModule struct {
+	BaseModule
+}
+
+func (m *netHTTPModule) RoutePrefix() string {
+	return "/api"
+}
+
+func (m *netHTTPModule) RegisterHTTPRoutes(adapter HTTPAdapter) {
+	adapter.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") == "" {
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+			w.Header().Set("X-Checked", "yes")
+			next.ServeHTTP(w, r)
+		})
+	})
+	adapter.Handle(http.MethodGet, "/items/:id", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "item %s", PathParam(r, "id"))
+	}))
+}
+
+func TestHTTPRouteModuleThroughGinAdapter(t *testing.T) {
+	module := NewTestingModule(&netHTTPModule{})
+	app, err := module.Compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer module.Close()
+	handler, err := app.Handler()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		auth   string
+		status int
+		body   string
+	}{
+		{"", http.StatusUnauthorized, "unauthorized\n"},
+		{"Bearer t", http.StatusOK, "item 42"},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/api/items/42", nil)
+		if tt.auth != "" {
+			req.Header.Set("Authorization", tt.auth)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != tt.status || rec.Body.String() != tt.body {
+			t.Errorf("auth %q: got %d %q, want %d %q", tt.auth, rec.Code, rec.Body.String(), tt.status, tt.body)
+		}
+	}
+
+	found := false
+	for _, route := range app.Adapter().Routes() {
+		found = found || route == RouteInfo{Method: http.MethodGet, Path: "/api/items/:id"}
+	}
+	if !found {
+		t.Errorf("Routes() = %v, want GET /api/items/:id", app.Adapter().Routes())
+	}
+}
@@ -2,8 +2,18 @@ package core
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
 	"sync"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/fx"
@@ -13,6 +23,34 @@ type ApplicationOptions struct {
 	Port    int    // Port to run the server on
 	Host    string // Host to run the server on
 	GinMode string // Gin mode (debug, release, test)
+	Profile string // Active environment profile (dev, test, prod)
+
+	EnabledModules []string // Named modules to load, nil loads every module
+
+	RoutingPolicy  RoutingPolicy // Trailing slash and case handling for all routes
+	MethodOverride bool          // Honour X-HTTP-Method-Override on POST requests
+	AutoHead       bool          // Serve HEAD for GET routes without a HEAD route
+
+	Quiet         bool      // Silence framework (fx) startup logging
+	StartupEvents io.Writer // Receives startup/shutdown events as JSON lines
+	LogLevel      LogLevel  // Level of the application logger, info by default
+
+	Clock Clock       // Time source provided to modules, defaults to the system clock
+	IDs   IDGenerator // ID generator provided to modules, defaults to UUIDv7
+
+	ShutdownSignals []os.Signal   // Signals that stop Run, none by default
+	DrainTimeout    time.Duration // How long Run waits for in-flight requests when stopping
+
+	ReadTimeout       time.Duration // See http.Server, zero means no timeout
+	ReadHeaderTimeout time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	TLSCertFile       string // Serve HTTPS with this certificate and key
+	TLSKeyFile        string
+	TLSConfig         *tls.Config  `json:"-"` // e.g. client certificate verification for mTLS
+	HTTPServer        *http.Server `json:"-"` // Server Run uses instead of building one
+
+	Profiling bool // Record per-stage pipeline timing, see Application.Profiler
 }
 
 // Default options
@@ -20,15 +58,45 @@ var defaultOptions = ApplicationOptions{
 	Port:    8080,
 	Host:    "localhost",
 	GinMode: gin.DebugMode,
+	Profile: "dev",
+	RoutingPolicy: RoutingPolicy{
+		TrailingSlash: TrailingSlashRedirect,
+	},
+	AutoHead:     true,
+	DrainTimeout: 30 * time.Second,
+}
+
+// InProfile reports whether the active profile is one of profiles
+func (o ApplicationOptions) InProfile(profiles ...string) bool {
+	for _, profile := range profiles {
+		if profile == o.Profile {
+			return true
+		}
+	}
+	return false
 }
 
 type Application struct {
 	mu        sync.RWMutex
 	container *Container
 	engine    *gin.Engine
+	adapter   *GinAdapter
+	links     *Links
 	modules   []Module
-	options   []fx.Option
-	config    ApplicationOptions
+	// HEAD route templates registered explicitly by modules
+	headRoutes []string
+	// Route templates registered by modules, as "METHOD path"
+	routes        []string
+	shutdownHooks []shutdownHook
+	lifecycle     *lifecycle
+	stop          chan ShutdownReason
+	profiler      *Profiler
+	hooks         *Hooks
+	logger        *Logger
+	fxApp         *fx.App
+	events        *eventWriter
+	options       []fx.Option
+	config        ApplicationOptions
 }
 
 // Option functions for configuration
@@ -50,25 +118,220 @@ func WithGinMode(mode string) func(*ApplicationOptions) {
 	}
 }
 
+// WithProfile sets the active profile. Defaults to $GOBLIN_PROFILE, or "dev".
+func WithProfile(profile string) func(*ApplicationOptions) {
+	return func(opts *ApplicationOptions) {
+		opts.Profile = profile
+	}
+}
+
+// WithEnabledModules selects which named modules are loaded, so one binary
+// can serve several deployment shapes. Defaults to $GOBLIN_ENABLED_MODULES.
+func WithEnabledModules(names ...string) func(*ApplicationOptions) {
+	return func(opts *ApplicationOptions) {
+		opts.EnabledModules = names
+	}
+}
+
+func WithRoutingPolicy(policy RoutingPolicy) func(*ApplicationOptions) {
+	return func(opts *ApplicationOptions) {
+		opts.RoutingPolicy = policy
+	}
+}
+
+// WithMethodOverride lets clients behind restrictive proxies tunnel PUT,
+// PATCH and DELETE through POST with X-HTTP-Method-Override
+func WithMethodOverride() func(*ApplicationOptions) {
+	return func(opts *ApplicationOptions) {
+		opts.MethodOverride = true
+	}
+}
+
+func WithAutoHead(enabled bool) func(*ApplicationOptions) {
+	return func(opts *ApplicationOptions) {
+		opts.AutoHead = enabled
+	}
+}
+
+// WithQuiet silences the framework's free-form startup logging
+func WithQuiet() func(*ApplicationOptions) {
+	return func(opts *ApplicationOptions) {
+		opts.Quiet = true
+	}
+}
+
+// WithLogLevel sets the level the application logger starts at; it can be
+// changed later through Application.Logger
+func WithLogLevel(level LogLevel) func(*ApplicationOptions) {
+	return func(opts *ApplicationOptions) {
+		opts.LogLevel = level
+	}
+}
+
+// WithStartupEvents streams startup and shutdown events to w as JSON lines,
+// for CI and orchestration tooling
+func WithStartupEvents(w io.Writer) func(*ApplicationOptions) {
+	return func(opts *ApplicationOptions) {
+		opts.StartupEvents = w
+	}
+}
+
+// WithClock replaces the time source provided to modules, e.g. with a
+// FakeClock in tests or NewSystemClock(location) for a fixed time zone
+func WithClock(clock Clock) func(*ApplicationOptions) {
+	return func(opts *ApplicationOptions) {
+		opts.Clock = clock
+	}
+}
+
+// WithIDGenerator replaces the ID generator provided to modules, e.g. with a
+// SequenceIDGenerator for deterministic fixtures
+func WithIDGenerator(generator IDGenerator) func(*ApplicationOptions) {
+	return func(opts *ApplicationOptions) {
+		opts.IDs = generator
+	}
+}
+
+// WithShutdownSignals makes Run stop gracefully on signals, SIGINT and
+// SIGTERM when none are given
+func WithShutdownSignals(signals ...os.Signal) func(*ApplicationOptions) {
+	if len(signals) == 0 {
+		signals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+	}
+	return func(opts *ApplicationOptions) {
+		opts.ShutdownSignals = signals
+	}
+}
+
+// WithDrainTimeout sets how long Run waits for in-flight requests to finish
+// once it stops accepting connections. Connections still open afterwards
+// are closed.
+func WithDrainTimeout(timeout time.Duration) func(*ApplicationOptions) {
+	return func(opts *ApplicationOptions) {
+		opts.DrainTimeout = timeout
+	}
+}
+
+// WithTimeouts sets the server's read, write and idle (keep-alive)
+// timeouts. ReadHeaderTimeout defaults to the read timeout.
+func WithTimeouts(read, write, idle time.Duration) func(*ApplicationOptions) {
+	return func(opts *ApplicationOptions) {
+		opts.ReadTimeout = read
+		opts.WriteTimeout = write
+		opts.IdleTimeout = idle
+	}
+}
+
+func WithReadHeaderTimeout(timeout time.Duration) func(*ApplicationOptions) {
+	return func(opts *ApplicationOptions) {
+		opts.ReadHeaderTimeout = timeout
+	}
+}
+
+// WithTLS serves HTTPS with the certificate and key in PEM files
+func WithTLS(certFile, keyFile string) func(*ApplicationOptions) {
+	return func(opts *ApplicationOptions) {
+		opts.TLSCertFile = certFile
+		opts.TLSKeyFile = keyFile
+	}
+}
+
+// WithTLSConfig serves HTTPS with config, which either carries the
+// certificates itself or is combined with WithTLS
+func WithTLSConfig(config *tls.Config) func(*ApplicationOptions) {
+	return func(opts *ApplicationOptions) {
+		opts.TLSConfig = config
+	}
+}
+
+// WithHTTPServer makes Run serve with server, for settings the options do
+// not cover. Its Handler is replaced by the application pipeline; its
+// timeouts and TLS config are kept when set.
+func WithHTTPServer(server *http.Server) func(*ApplicationOptions) {
+	return func(opts *ApplicationOptions) {
+		opts.HTTPServer = server
+	}
+}
+
+// WithProfiling records how long each request spends in middleware, guards,
+// the handler and writing the response. In debug mode responses carry a
+// Server-Timing header.
+func WithProfiling() func(*ApplicationOptions) {
+	return func(opts *ApplicationOptions) {
+		opts.Profiling = true
+	}
+}
+
 func NewGoblinApplication(opts ...func(*ApplicationOptions)) *Application {
 	// Start with default options
 	config := defaultOptions
+	if profile := os.Getenv("GOBLIN_PROFILE"); profile != "" {
+		config.Profile = profile
+	}
+	if enabled := os.Getenv("GOBLIN_ENABLED_MODULES"); enabled != "" {
+		config.EnabledModules = strings.Split(enabled, ",")
+	}
 
 	// Apply any provided options
 	for _, opt := range opts {
 		opt(&config)
 	}
 
+	if config.Clock == nil {
+		config.Clock = NewSystemClock(nil)
+	}
+	if config.IDs == nil {
+		config.IDs = NewUUIDv7Generator(config.Clock)
+	}
+
 	// Set Gin mode
 	gin.SetMode(config.GinMode)
 
-	return &Application{
-		container: NewContainer(),
-		engine:    gin.Default(),
+	engine := gin.Default()
+	// The pipeline probe must see the whole chain, so it runs before
+	// gin's own logger and recovery
+	engine.Handlers = append(gin.HandlersChain{probeHandler}, engine.Handlers...)
+	links := NewLinks()
+
+	var profiler *Profiler
+	if config.Profiling {
+		profiler = NewProfiler()
+		engine.Use(profiler.Middleware())
+	}
+
+	// Expose the link builder to handlers through RouteURL
+	engine.Use(links.middleware())
+
+	hooks := NewHooks()
+	engine.Use(hooks.Middleware())
+
+	// Let handlers resolve per-application state through RequestContainer
+	container := NewContainer()
+	engine.Use(container.middleware())
+
+	app := &Application{
+		container: container,
+		engine:    engine,
+		adapter:   NewGinAdapter(engine),
+		links:     links,
 		modules:   make([]Module, 0),
 		options:   make([]fx.Option, 0),
 		config:    config,
+		events:    &eventWriter{out: config.StartupEvents},
+		lifecycle: newLifecycle(),
+		stop:      make(chan ShutdownReason, 1),
+		profiler:  profiler,
+		hooks:     hooks,
+		logger:    NewLogger(config.LogLevel),
 	}
+	Bind[Clock](app.container, config.Clock)
+	Bind[IDGenerator](app.container, config.IDs)
+	Bind[*Hooks](app.container, hooks)
+	Bind[*Logger](app.container, app.logger)
+	hooks.log = app.logger
+	app.applyRoutingPolicy()
+
+	return app
 }
 
 func (app *Application) AddModule(module Module) {
@@ -77,98 +340,463 @@ func (app *Application) AddModule(module Module) {
 	app.modules = append(app.modules, module)
 }
 
-func (app *Application) Configure() {
+// Configure prepares every module. Calling it again once it succeeded does
+// nothing.
+func (app *Application) Configure() error {
 	app.mu.Lock()
 	defer app.mu.Unlock()
 
-	// Configure all modules
-	for _, module := range app.modules {
-		// Initialize module
-		module.Configure(app.container)
+	switch state := app.lifecycle.current(); state {
+	case StateConfigured:
+		return nil
+	case StateCreated:
+	default:
+		return &StateTransitionError{From: state, To: StateConfigured}
+	}
 
-		// Add module's fx options if available
-		if fxModule, ok := module.(FxModule); ok {
-			app.options = append(app.options, fxModule.ProvideDependencies())
+	// Drop modules whose condition does not hold for this application
+	active := make([]Module, 0, len(app.modules))
+	for _, module := range app.modules {
+		if conditional, ok := module.(ConditionalModule); ok {
+			if condition := conditional.Condition(); condition != nil && !condition(app.config) {
+				continue
+			}
 		}
+		active = append(active, module)
+	}
+
+	// Keep the modules enabled for this deployment
+	selected, err := selectModules(active, app.config.EnabledModules)
+	if err != nil {
+		app.events.emit(StartupEvent{Event: EventAppFailed, Phase: "select", Error: err.Error()})
+		app.lifecycle.transition(StateFailed)
+		return err
+	}
+	app.modules = selected
+
+	// Configure all modules before initializing any, so capabilities
+	// published in Configure can be resolved from OnInit
+	for _, module := range app.modules {
+		start := time.Now()
+		name := fmt.Sprintf("%T", module)
+
+		stack, err := callSafely(func() error {
+			// Initialize module
+			module.Configure(app.container)
 
-		// Call lifecycle hooks if available
-		if lifecycleModule, ok := module.(LifecycleModule); ok {
-			if err := lifecycleModule.OnInit(); err != nil {
-				panic(err)
+			// Add module's fx options if available
+			if fxModule, ok := module.(FxModule); ok {
+				app.options = append(app.options, fxModule.ProvideDependencies())
 			}
+			return nil
+		})
+		if err != nil {
+			return app.failStartup(&StartupError{Module: name, Phase: "configure", Err: err, Stack: stack}, nil)
 		}
+
+		app.events.emit(StartupEvent{
+			Event:    EventModuleConfigured,
+			Module:   name,
+			Duration: milliseconds(time.Since(start)),
+		})
+	}
+
+	// Call lifecycle hooks if available
+	initialized := make([]LifecycleModule, 0)
+	for _, module := range app.modules {
+		lifecycleModule, ok := module.(LifecycleModule)
+		if !ok {
+			continue
+		}
+
+		start := time.Now()
+		name := fmt.Sprintf("%T", module)
+		if stack, err := callSafely(lifecycleModule.OnInit); err != nil {
+			return app.failStartup(&StartupError{Module: name, Phase: "init", Err: err, Stack: stack}, initialized)
+		}
+		initialized = append(initialized, lifecycleModule)
+		app.events.emit(StartupEvent{
+			Event:    EventModuleInitialized,
+			Module:   name,
+			Duration: milliseconds(time.Since(start)),
+		})
+	}
+
+	if app.quiet() {
+		app.options = append(app.options, fx.NopLogger)
 	}
 
 	// Configure Fx
 	app.options = append(app.options,
 		fx.Provide(
 			func() *gin.Engine { return app.engine },
+			func() HTTPAdapter { return app.adapter },
 			func() *Container { return app.container },
+			func() Clock { return app.config.Clock },
+			func() IDGenerator { return app.config.IDs },
+			func() *Hooks { return app.hooks },
+			func() *Logger { return app.logger },
 		),
 		fx.Invoke(app.registerRoutes),
 	)
+	return app.lifecycle.transition(StateConfigured)
 }
 
-func (app *Application) Run(ctx context.Context) error {
+// failStartup destroys the modules initialized so far, newest first, and
+// marks the application failed
+func (app *Application) failStartup(startupErr *StartupError, initialized []LifecycleModule) error {
+	app.events.emit(StartupEvent{
+		Event:  EventModuleFailed,
+		Module: startupErr.Module,
+		Phase:  startupErr.Phase,
+		Error:  startupErr.Err.Error(),
+	})
+	if startupErr.Stack != nil {
+		app.logger.Errorf("Module %s panicked during %s: %v\n%s", startupErr.Module, startupErr.Phase, startupErr.Err, startupErr.Stack)
+	}
+
+	for i := len(initialized) - 1; i >= 0; i-- {
+		if _, err := callSafely(initialized[i].OnDestroy); err != nil {
+			app.logger.Errorf("Module %T failed to destroy after startup failure: %v", initialized[i], err)
+		}
+	}
+	if err := app.container.Dispose(); err != nil {
+		app.logger.Errorf("Container cleanup failed after startup failure: %v", err)
+	}
+
+	app.lifecycle.transition(StateFailed)
+	return startupErr
+}
+
+// start builds the request pipeline: fx starts and routes are registered
+func (app *Application) start(ctx context.Context) error {
+	if err := app.lifecycle.transition(StateStarting); err != nil {
+		return err
+	}
+
 	// Create Fx application with all options
 	fxApp := fx.New(app.options...)
 
 	// Start the application
 	if err := fxApp.Start(ctx); err != nil {
+		app.events.emit(StartupEvent{Event: EventAppFailed, Phase: "start", Error: err.Error()})
+		return app.abortStart(err)
+	}
+
+	app.mu.Lock()
+	app.fxApp = fxApp
+	app.mu.Unlock()
+	return nil
+}
+
+// abortStart destroys the modules Configure initialized when starting
+// fails after it, and marks the application failed
+func (app *Application) abortStart(err error) error {
+	cleanupErr := app.cleanup(ShutdownReason{Kind: ShutdownError, Err: err})
+	app.lifecycle.transition(StateFailed)
+	return errors.Join(err, cleanupErr)
+}
+
+// Handler builds the full pipeline without listening, so the application
+// can be mounted into an existing server, a serverless adapter or a test
+// harness. Calling it again returns the same pipeline. Stop an embedded
+// application with Shutdown.
+func (app *Application) Handler() (http.Handler, error) {
+	if app.lifecycle.current() == StateRunning {
+		return app.handler(), nil
+	}
+	if err := app.start(context.Background()); err != nil {
+		return nil, err
+	}
+	if err := app.lifecycle.transition(StateRunning); err != nil {
+		return nil, err
+	}
+	return app.handler(), nil
+}
+
+// Run serves the pipeline built by Handler on the configured address until
+// ctx ends, Stop is called, a shutdown signal arrives or the server fails.
+// It then stops accepting connections, waits up to the drain timeout for
+// in-flight requests, and runs the shutdown hooks and OnDestroy.
+func (app *Application) Run(ctx context.Context) error {
+	if err := app.start(ctx); err != nil {
 		return err
 	}
+	handler := app.handler()
+
+	addr := fmt.Sprintf("%s:%d", app.config.Host, app.config.Port)
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		app.events.emit(StartupEvent{Event: EventAppFailed, Phase: "listen", Addr: addr, Error: err.Error()})
+		return errors.Join(app.abortStart(err), app.stopFx())
+	}
+	if err := app.lifecycle.transition(StateRunning); err != nil {
+		listener.Close()
+		return errors.Join(err, app.stopFx())
+	}
+	app.events.emit(StartupEvent{Event: EventAppListening, Addr: listener.Addr().String()})
 
 	// Create a channel for server errors
 	errChan := make(chan error, 1)
 
 	// Start HTTP server in a goroutine
+	server := app.httpServer(handler)
 	go func() {
-		addr := fmt.Sprintf("%s:%d", app.config.Host, app.config.Port)
-		if err := app.engine.Run(addr); err != nil {
+		var err error
+		if server.TLSConfig != nil || app.config.TLSCertFile != "" {
+			err = server.ServeTLS(listener, app.config.TLSCertFile, app.config.TLSKeyFile)
+		} else {
+			err = server.Serve(listener)
+		}
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
 			errChan <- err
 		}
 	}()
 
-	// Wait for either context cancellation or server error
+	var signals chan os.Signal
+	if len(app.config.ShutdownSignals) > 0 {
+		signals = make(chan os.Signal, 1)
+		signal.Notify(signals, app.config.ShutdownSignals...)
+		defer signal.Stop(signals)
+	}
+
+	// Wait for a signal, Stop, context cancellation or server error
+	var reason ShutdownReason
 	select {
 	case <-ctx.Done():
-		return app.cleanup()
+		reason = ShutdownReason{Kind: ShutdownContext, Err: ctx.Err()}
+	case sig := <-signals:
+		reason = ShutdownReason{Kind: ShutdownSignal, Signal: sig}
+	case reason = <-app.stop:
 	case err := <-errChan:
-		return err
+		reason = ShutdownReason{Kind: ShutdownError, Err: err}
+	}
+	return app.shutdown(reason, server)
+}
+
+// Shutdown stops an application started with Handler
+func (app *Application) Shutdown() error {
+	if state := app.lifecycle.current(); state != StateRunning {
+		return &StateTransitionError{From: state, To: StateStopping}
 	}
+	return app.shutdown(ShutdownReason{Kind: ShutdownStop}, nil)
 }
 
-func (app *Application) cleanup() error {
+// shutdown drains server, when Run started one, then stops the modules
+func (app *Application) shutdown(reason ShutdownReason, server *http.Server) error {
+	var drainErr error
+	if err := app.lifecycle.transition(StateStopping); err != nil {
+		// Another shutdown got there first, e.g. Shutdown racing a signal.
+		// Wait for it rather than running the hooks and OnDestroy twice.
+		if server != nil {
+			drainErr = app.drain(server)
+		}
+		app.lifecycle.settled()
+		return drainErr
+	}
+
+	if server != nil {
+		drainErr = app.drain(server)
+	}
+
+	err := errors.Join(drainErr, app.cleanup(reason), app.stopFx())
+	if reason.Kind == ShutdownError {
+		app.events.emit(StartupEvent{Event: EventAppFailed, Phase: "serve", Error: reason.Err.Error()})
+		app.lifecycle.transition(StateFailed)
+		return errors.Join(reason.Err, err)
+	}
+	app.events.emit(StartupEvent{Event: EventAppStopped, Reason: reason.String()})
+	app.lifecycle.transition(StateStopped)
+	return err
+}
+
+// httpServer returns the server Run uses, filling in the options the
+// supplied server, if any, leaves unset
+func (app *Application) httpServer(handler http.Handler) *http.Server {
+	server := app.config.HTTPServer
+	if server == nil {
+		server = &http.Server{}
+	}
+	server.Handler = handler
+
+	if server.ReadTimeout == 0 {
+		server.ReadTimeout = app.config.ReadTimeout
+	}
+	if server.ReadHeaderTimeout == 0 {
+		server.ReadHeaderTimeout = app.config.ReadHeaderTimeout
+	}
+	if server.WriteTimeout == 0 {
+		server.WriteTimeout = app.config.WriteTimeout
+	}
+	if server.IdleTimeout == 0 {
+		server.IdleTimeout = app.config.IdleTimeout
+	}
+	if server.TLSConfig == nil {
+		server.TLSConfig = app.config.TLSConfig
+	}
+	return server
+}
+
+// drain stops accepting connections and waits for in-flight requests,
+// closing whatever is left when the drain timeout runs out
+func (app *Application) drain(server *http.Server) error {
+	ctx, cancel := context.WithTimeout(context.Background(), app.config.DrainTimeout)
+	defer cancel()
+
+	if err := server.Shutdown(ctx); err != nil {
+		server.Close()
+		return fmt.Errorf("draining connections: %w", err)
+	}
+	return nil
+}
+
+// stopFx runs the fx OnStop hooks
+func (app *Application) stopFx() error {
+	app.mu.RLock()
+	fxApp := app.fxApp
+	app.mu.RUnlock()
+	if fxApp == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultShutdownHookTimeout)
+	defer cancel()
+	return fxApp.Stop(ctx)
+}
+
+func (app *Application) cleanup(reason ShutdownReason) error {
+	// The run context is already done, hooks get their own deadlines
+	hookErr := app.runShutdownHooks(context.Background(), reason)
+
 	app.mu.Lock()
 	defer app.mu.Unlock()
 
-	// Call OnDestroy for all modules that implement LifecycleModule
+	errs := []error{hookErr}
 	for _, module := range app.modules {
-		if lifecycleModule, ok := module.(LifecycleModule); ok {
-			if err := lifecycleModule.OnDestroy(); err != nil {
-				return err
+		if shutdownModule, ok := module.(ShutdownModule); ok {
+			if _, err := callSafely(func() error { return shutdownModule.OnApplicationShutdown(reason) }); err != nil {
+				errs = append(errs, fmt.Errorf("%T: %w", module, err))
 			}
 		}
 	}
-	return nil
+
+	// Call OnDestroy in reverse order of initialization, carrying on past
+	// failing modules
+	for i := len(app.modules) - 1; i >= 0; i-- {
+		if lifecycleModule, ok := app.modules[i].(LifecycleModule); ok {
+			if _, err := callSafely(lifecycleModule.OnDestroy); err != nil {
+				errs = append(errs, fmt.Errorf("%T: %w", app.modules[i], err))
+			}
+		}
+	}
+
+	// Release what modules provided through the container last
+	errs = append(errs, app.container.Dispose())
+	return errors.Join(errs...)
 }
 
-func (app *Application) registerRoutes() {
-	app.mu.RLock()
-	defer app.mu.RUnlock()
+func (app *Application) registerRoutes(global globalHandlers) error {
+	app.mu.Lock()
+	defer app.mu.Unlock()
+
+	// Global middleware and guards provided by modules run before any route
+	app.engine.Use(global.Middleware...)
+	for _, guard := range global.Guards {
+		app.engine.Use(GuardToMiddleware(guard))
+	}
+
+	// Register every module once, attributing the routes each one adds to
+	// it. gin panics on a conflicting route; the panic is recovered and the
+	// remaining modules still register, so all conflicts are reported at once.
+	owners := make([]routeOwner, 0)
+	registered := make(map[string]bool)
+	own := func(module string) {
+		for _, route := range app.engine.Routes() {
+			if key := route.Method + " " + route.Path; !registered[key] {
+				registered[key] = true
+				owners = append(owners, routeOwner{method: route.Method, path: route.Path, module: module})
+			}
+		}
+	}
+	own("application")
 
+	conflicts := make([]RouteConflict, 0)
 	for _, module := range app.modules {
-		if routeModule, ok := module.(RouteModule); ok {
-			routeModule.RegisterRoutes(app.engine.Group(""))
+		var reason string
+		switch routeModule := module.(type) {
+		case RouteModule:
+			reason = registerModuleRoutes(routeModule, moduleGroup(app.engine, routeModule, app.profiler != nil))
+		case HTTPRouteModule:
+			reason = registerHTTPRoutes(routeModule, app.adapter)
+		default:
+			continue
+		}
+
+		name := fmt.Sprintf("%T", module)
+		own(name)
+		if reason != "" {
+			conflicts = append(conflicts, RouteConflict{
+				Module:   name,
+				Existing: conflictingOwners(owners, reason),
+				Reason:   reason,
+			})
+		}
+	}
+	if len(conflicts) > 0 {
+		return &RouteConflictError{Conflicts: conflicts}
+	}
+
+	app.events.emit(StartupEvent{Event: EventRoutesRegistered, Routes: len(owners)})
+	for _, owner := range owners {
+		if owner.method == http.MethodHead {
+			app.headRoutes = append(app.headRoutes, owner.path)
 		}
+		app.routes = append(app.routes, owner.method+" "+owner.path)
 	}
+	app.links.Index(app.engine.Routes())
+	return nil
+}
+
+// quiet reports whether free-form framework logging is silenced, by
+// WithQuiet or in favour of the startup event stream
+func (app *Application) quiet() bool {
+	return app.config.Quiet || app.config.StartupEvents != nil
 }
 
-// GetEngine returns the underlying Gin engine
+// GetEngine returns the underlying Gin engine. AutoHead, MethodOverride
+// and TrailingSlashIgnore wrap the engine in Handler and Run, so serving
+// the engine directly goes without them.
 func (app *Application) GetEngine() *gin.Engine {
 	return app.engine
 }
 
+// Adapter returns the HTTP adapter the application serves requests
+// through, for registering routes and middleware without gin
+func (app *Application) Adapter() HTTPAdapter {
+	return app.adapter
+}
+
+// RouteURL builds a path for the named handler, e.g.
+// RouteURL("user.Controller.GetUser", id)
+func (app *Application) RouteURL(name string, params ...interface{}) (string, error) {
+	return app.links.URL(name, params...)
+}
+
+// Logger returns the logger the application and its middleware write to
+func (app *Application) Logger() *Logger {
+	return app.logger
+}
+
+// Hooks returns the registry of internal hook points
+func (app *Application) Hooks() *Hooks {
+	return app.hooks
+}
+
+// Profiler returns the pipeline profiler, or nil unless WithProfiling is set
+func (app *Application) Profiler() *Profiler {
+	return app.profiler
+}
+
 // GetContainer returns the dependency container
 func (app *Application) GetContainer() *Container {
 	return app.container
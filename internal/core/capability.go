@@ -0,0 +1,50 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrCapabilityNotFound = errors.New("capability not found")
+	ErrCapabilityType     = errors.New("capability has unexpected type")
+)
+
+// Publish registers impl under a capability name such as "user.lookup", so
+// other modules can use it without importing the publishing module.
+// Publish from Configure; consumers resolve from OnInit or later.
+func Publish[T any](c *Container, name string, impl T) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.capabilities[name] = impl
+}
+
+// Capability resolves a published capability as T
+func Capability[T any](c *Container, name string) (T, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	var zero T
+	impl, exists := c.capabilities[name]
+	if !exists {
+		return zero, fmt.Errorf("%w: %s", ErrCapabilityNotFound, name)
+	}
+
+	typed, ok := impl.(T)
+	if !ok {
+		return zero, fmt.Errorf("%w: %s is %T, want %v", ErrCapabilityType, name, impl, typeOf[T]())
+	}
+	return typed, nil
+}
+
+// Capabilities lists the published capability names
+func (c *Container) Capabilities() []string {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	names := make([]string, 0, len(c.capabilities))
+	for name := range c.capabilities {
+		names = append(names, name)
+	}
+	return names
+}
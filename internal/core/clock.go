@@ -0,0 +1,120 @@
+package core
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock is the source of time for the framework and for services that want
+// time-dependent logic to be testable without sleeping
+type Clock interface {
+	Now() time.Time
+	Since(t time.Time) time.Duration
+	After(d time.Duration) <-chan time.Time
+	Sleep(d time.Duration)
+}
+
+// ResolveClock returns the clock bound in the container, or the system clock
+func ResolveClock(c *Container) Clock {
+	if clock, err := Resolve[Clock](c); err == nil {
+		return clock
+	}
+	return NewSystemClock(nil)
+}
+
+type systemClock struct {
+	location *time.Location
+}
+
+// NewSystemClock returns the real clock reporting times in location, or in
+// the local time zone when location is nil
+func NewSystemClock(location *time.Location) Clock {
+	if location == nil {
+		location = time.Local
+	}
+	return systemClock{location: location}
+}
+
+func (c systemClock) Now() time.Time                         { return time.Now().In(c.location) }
+func (c systemClock) Since(t time.Time) time.Duration        { return time.Since(t) }
+func (c systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+func (c systemClock) Sleep(d time.Duration)                  { time.Sleep(d) }
+
+// FakeClock only moves when told to, firing the timers it passes
+type FakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []fakeWaiter
+}
+
+type fakeWaiter struct {
+	deadline time.Time
+	ch       chan time.Time
+}
+
+func NewFakeClock(start time.Time) *FakeClock {
+	return &FakeClock{now: start}
+}
+
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *FakeClock) Since(t time.Time) time.Duration {
+	return c.Now().Sub(t)
+}
+
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- c.now
+		return ch
+	}
+	c.waiters = append(c.waiters, fakeWaiter{deadline: c.now.Add(d), ch: ch})
+	return ch
+}
+
+// Sleep blocks until the clock has been advanced by d
+func (c *FakeClock) Sleep(d time.Duration) {
+	<-c.After(d)
+}
+
+// Advance moves the clock forward by d
+func (c *FakeClock) Advance(d time.Duration) {
+	c.Set(c.Now().Add(d))
+}
+
+// Set moves the clock to t, firing every timer due by then in order
+func (c *FakeClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = t
+	sort.SliceStable(c.waiters, func(i, j int) bool {
+		return c.waiters[i].deadline.Before(c.waiters[j].deadline)
+	})
+
+	pending := c.waiters[:0]
+	for _, waiter := range c.waiters {
+		if waiter.deadline.After(t) {
+			pending = append(pending, waiter)
+			continue
+		}
+		waiter.ch <- t
+	}
+	c.waiters = pending
+}
+
+// Waiters reports how many timers are pending, so tests can wait until the
+// code under test is blocked on the clock before advancing it
+func (c *FakeClock) Waiters() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.waiters)
+}
@@ -2,20 +2,33 @@ package core
 
 import (
 	"errors"
+	"fmt"
 	"reflect"
 	"sync"
+
+	"github.com/gin-gonic/gin"
 )
 
-var ErrNotFound = errors.New("dependency not found")
+var (
+	ErrNotFound       = errors.New("dependency not found")
+	ErrDependencyType = errors.New("dependency has unexpected type")
+)
 
 type Container struct {
-	mutex      sync.RWMutex
-	containers map[reflect.Type]interface{}
+	mutex        sync.RWMutex
+	containers   map[reflect.Type]interface{}
+	overrides    map[reflect.Type]interface{}
+	capabilities map[string]interface{}
+	groups       map[string][]interface{}
+	cleanups     []interface{}
 }
 
-func NewContainer() *Container {	
+func NewContainer() *Container {
 	return &Container{
-		containers: make(map[reflect.Type]interface{}),
+		containers:   make(map[reflect.Type]interface{}),
+		overrides:    make(map[reflect.Type]interface{}),
+		capabilities: make(map[string]interface{}),
+		groups:       make(map[string][]interface{}),
 	}
 }
 
@@ -29,8 +42,98 @@ func (c *Container) Resolve(interfaceType reflect.Type) (interface{}, error) {
 	c.mutex.RLock()
 	defer c.mutex.RUnlock()
 
+	if implementation, exists := c.overrides[interfaceType]; exists {
+		return implementation, nil
+	}
 	if implementation, exists := c.containers[interfaceType]; exists {
 		return implementation, nil
 	}
 	return nil, ErrNotFound
 }
+
+// override makes interfaceType resolve to implementation whatever is bound
+// later, see TestingModule
+func (c *Container) override(interfaceType reflect.Type, implementation interface{}) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.overrides[interfaceType] = implementation
+}
+
+// Bind registers implementation under T without spelling out the reflect
+// type, e.g. Bind[Clock](c, clock)
+func Bind[T any](c *Container, implementation T) {
+	c.Bind(typeOf[T](), implementation)
+}
+
+// Resolve returns the implementation bound under T
+func Resolve[T any](c *Container) (T, error) {
+	var zero T
+	implementation, err := c.Resolve(typeOf[T]())
+	if err != nil {
+		return zero, fmt.Errorf("%w: %v", err, typeOf[T]())
+	}
+
+	typed, ok := implementation.(T)
+	if !ok {
+		return zero, fmt.Errorf("%w: %v is bound to %T", ErrDependencyType, typeOf[T](), implementation)
+	}
+	return typed, nil
+}
+
+// ResolveOrBind returns the implementation bound under T, binding the one
+// create returns on first use. Concurrent callers share one instance.
+func ResolveOrBind[T any](c *Container, create func() T) T {
+	if implementation, err := Resolve[T](c); err == nil {
+		return implementation
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	interfaceType := typeOf[T]()
+	if implementation, exists := c.overrides[interfaceType]; exists {
+		if typed, ok := implementation.(T); ok {
+			return typed
+		}
+	}
+	if implementation, exists := c.containers[interfaceType]; exists {
+		if typed, ok := implementation.(T); ok {
+			return typed
+		}
+	}
+	implementation := create()
+	c.containers[interfaceType] = implementation
+	return implementation
+}
+
+// containerKey is the gin context key holding the application's container
+const containerKey = "goblin.container"
+
+// RequestContainer returns the container of the application serving c, or
+// nil outside an application, so packages can resolve per-application
+// state while handling a request
+func RequestContainer(c *gin.Context) *Container {
+	value, _ := c.Get(containerKey)
+	container, _ := value.(*Container)
+	return container
+}
+
+func (c *Container) middleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Set(containerKey, c)
+		ctx.Next()
+	}
+}
+
+// MustResolve is Resolve for dependencies a module cannot start without;
+// it panics when T is not bound
+func MustResolve[T any](c *Container) T {
+	implementation, err := Resolve[T](c)
+	if err != nil {
+		panic(err)
+	}
+	return implementation
+}
+
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
@@ -0,0 +1,90 @@
+package core
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+type greeter interface {
+	Greet() string
+}
+
+type englishGreeter struct{}
+
+func (englishGreeter) Greet() string { return "hello" }
+
+func TestBindAndResolve(t *testing.T) {
+	c := NewContainer()
+	Bind[greeter](c, englishGreeter{})
+
+	got, err := Resolve[greeter](c)
+	if err != nil || got.Greet() != "hello" {
+		t.Fatalf("Resolve[greeter] = %v, %v", got, err)
+	}
+	if got := MustResolve[greeter](c); got.Greet() != "hello" {
+		t.Errorf("MustResolve[greeter] = %v", got)
+	}
+
+	// Bound under the interface, not under the concrete type
+	if _, err := Resolve[englishGreeter](c); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Resolve[englishGreeter] error = %v, want ErrNotFound", err)
+	}
+
+	c.Bind(reflect.TypeOf((*greeter)(nil)).Elem(), "not a greeter")
+	if _, err := Resolve[greeter](c); !errors.Is(err, ErrDependencyType) {
+		t.Errorf("Resolve of a mistyped binding error = %v, want ErrDependencyType", err)
+	}
+}
+
+func TestMustResolvePanicsWhenUnbound(t *testing.T) {
+	defer func() {
+		err, _ := recover().(error)
+		if !errors.Is(err, ErrNotFound) {
+			t.Errorf("recovered %v, want ErrNotFound", err)
+		}
+	}()
+	MustResolve[greeter](NewContainer())
+}
+
+func TestResolveOrBindSharesOneInstance(t *testing.T) {
+	c := NewContainer()
+	created := 0
+	var mu sync.Mutex
+	create := func() *FakeClock {
+		mu.Lock()
+		defer mu.Unlock()
+		created++
+		return NewFakeClock(time.Time{})
+	}
+
+	clocks := make([]*FakeClock, 16)
+	var wg sync.WaitGroup
+	for i := range clocks {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			clocks[i] = ResolveOrBind(c, create)
+		}(i)
+	}
+	wg.Wait()
+
+	if created != 1 {
+		t.Errorf("create ran %d times, want 1", created)
+	}
+	for _, clock := range clocks {
+		if clock != clocks[0] {
+			t.Fatal("callers got different instances")
+		}
+	}
+
+	// An existing binding wins over create
+	bound := NewFakeClock(time.Time{})
+	other := NewContainer()
+	Bind(other, bound)
+	if got := ResolveOrBind(other, create); got != bound {
+		t.Error("ResolveOrBind replaced an existing binding")
+	}
+}
@@ -0,0 +1,63 @@
+package core
+
+import (
+	"context"
+	"runtime/debug"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PropagatedKeys are the gin context keys Detach always carries over
+var PropagatedKeys = []string{"RequestID"}
+
+// detachedContext serves selected request values without the request's
+// cancellation
+type detachedContext struct {
+	context.Context
+	values map[string]interface{}
+}
+
+func (c *detachedContext) Value(key interface{}) interface{} {
+	if name, ok := key.(string); ok {
+		if value, exists := c.values[name]; exists {
+			return value
+		}
+	}
+	return c.Context.Value(key)
+}
+
+// Detach returns a context for background work started by a request. It
+// keeps the request context's values (locale, trace span) and the gin keys
+// in PropagatedKeys and keys (request ID, principal), read back with
+// ctx.Value("RequestID"), but is not canceled when the request ends.
+func Detach(c *gin.Context, keys ...string) context.Context {
+	values := make(map[string]interface{}, len(PropagatedKeys)+len(keys))
+	for _, list := range [][]string{PropagatedKeys, keys} {
+		for _, key := range list {
+			if value, exists := c.Get(key); exists {
+				values[key] = value
+			}
+		}
+	}
+	return &detachedContext{
+		Context: context.WithoutCancel(c.Request.Context()),
+		values:  values,
+	}
+}
+
+// Go runs fn in the background with a detached context, logging panics and
+// errors with the originating request ID
+func Go(c *gin.Context, fn func(ctx context.Context) error, keys ...string) {
+	ctx := Detach(c, keys...)
+	logger := RequestLogger(c)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				logger.Errorf("Background task panic [request %v]: %v\n%s", ctx.Value("RequestID"), r, debug.Stack())
+			}
+		}()
+		if err := fn(ctx); err != nil {
+			logger.Errorf("Background task failed [request %v]: %v", ctx.Value("RequestID"), err)
+		}
+	}()
+}
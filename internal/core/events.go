@@ -0,0 +1,60 @@
+package core
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+const (
+	EventModuleConfigured  = "module.configured"
+	EventModuleInitialized = "module.initialized"
+	EventModuleFailed      = "module.failed"
+	EventRoutesRegistered  = "routes.registered"
+	EventAppListening      = "app.listening"
+	EventAppFailed         = "app.failed"
+	EventAppStopped        = "app.stopped"
+	EventShutdownHook      = "shutdown.hook"
+)
+
+// StartupEvent is one line of the machine-readable startup/shutdown stream
+type StartupEvent struct {
+	Time     time.Time `json:"time"`
+	Event    string    `json:"event"`
+	Module   string    `json:"module,omitempty"`
+	Phase    string    `json:"phase,omitempty"`
+	Duration float64   `json:"duration_ms,omitempty"`
+	Addr     string    `json:"addr,omitempty"`
+	Routes   int       `json:"routes,omitempty"`
+	Reason   string    `json:"reason,omitempty"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// eventWriter writes startup events as JSON lines
+type eventWriter struct {
+	mu  sync.Mutex
+	out io.Writer
+}
+
+func (w *eventWriter) emit(event StartupEvent) {
+	if w == nil || w.out == nil {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.out.Write(append(data, '\n'))
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,192 @@
+package core
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FileOptions struct {
+	Name        string // File name offered to the client, defaults to the base name
+	Attachment  bool   // Ask the client to download rather than display
+	ContentType string // Overrides detection by extension and content sniffing
+
+	// Hand the transfer to a front proxy instead of streaming it from Go
+	SendfileHeader string // "X-Accel-Redirect" (nginx) or "X-Sendfile" (Apache, lighttpd)
+	SendfileRoot   string // Directory the proxy maps; files outside it are served directly
+	SendfilePrefix string // Internal location the root is mapped to, for X-Accel-Redirect
+}
+
+func WithFileName(name string) func(*FileOptions) {
+	return func(o *FileOptions) {
+		o.Name = name
+	}
+}
+
+func WithContentType(contentType string) func(*FileOptions) {
+	return func(o *FileOptions) {
+		o.ContentType = contentType
+	}
+}
+
+// WithAccelRedirect lets nginx send files under root, which its config
+// exposes as the internal location prefix
+func WithAccelRedirect(root, prefix string) func(*FileOptions) {
+	return func(o *FileOptions) {
+		o.SendfileHeader = "X-Accel-Redirect"
+		o.SendfileRoot = root
+		o.SendfilePrefix = prefix
+	}
+}
+
+// WithSendfile lets the front server send files under root by absolute path
+func WithSendfile(root string) func(*FileOptions) {
+	return func(o *FileOptions) {
+		o.SendfileHeader = "X-Sendfile"
+		o.SendfileRoot = root
+	}
+}
+
+// File serves the file at name for display, with Range, If-Modified-Since
+// and content type detection handled by http.ServeContent
+func File(c *gin.Context, name string, opts ...func(*FileOptions)) {
+	options := FileOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	serveFile(c, name, options)
+}
+
+// Attachment serves the file at name as a download called filename
+func Attachment(c *gin.Context, name, filename string, opts ...func(*FileOptions)) {
+	options := FileOptions{Name: filename, Attachment: true}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	serveFile(c, name, options)
+}
+
+// Content serves content that is not on disk, such as a generated report or
+// a blob from object storage, with the same Range support as File
+func Content(c *gin.Context, name string, modtime time.Time, content io.ReadSeeker, opts ...func(*FileOptions)) {
+	options := FileOptions{Name: name}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	setContentHeaders(c, options)
+	http.ServeContent(c.Writer, c.Request, options.Name, modtime, content)
+}
+
+func serveFile(c *gin.Context, name string, options FileOptions) {
+	f, err := os.Open(name)
+	if err != nil {
+		fileError(c, err)
+		return
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		fileError(c, err)
+		return
+	}
+	if info.IsDir() {
+		fileError(c, fs.ErrNotExist)
+		return
+	}
+
+	if options.Name == "" {
+		options.Name = info.Name()
+	}
+	if target, ok := sendfileTarget(name, options); ok {
+		if options.ContentType == "" {
+			options.ContentType = detectContentType(f, options.Name)
+		}
+		setContentHeaders(c, options)
+		c.Header(options.SendfileHeader, target)
+		c.Status(http.StatusOK)
+		return
+	}
+
+	setContentHeaders(c, options)
+	http.ServeContent(c.Writer, c.Request, options.Name, info.ModTime(), f)
+}
+
+// sendfileTarget returns the value of the sendfile header for name, if the
+// file is under the sendfile root
+func sendfileTarget(name string, options FileOptions) (string, bool) {
+	if options.SendfileHeader == "" || options.SendfileRoot == "" {
+		return "", false
+	}
+
+	abs, err := filepath.Abs(name)
+	if err != nil {
+		return "", false
+	}
+	root, err := filepath.Abs(options.SendfileRoot)
+	if err != nil {
+		return "", false
+	}
+	rel, err := filepath.Rel(root, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+
+	if options.SendfilePrefix == "" {
+		return abs, true
+	}
+	return path.Join(options.SendfilePrefix, filepath.ToSlash(rel)), true
+}
+
+func setContentHeaders(c *gin.Context, options FileOptions) {
+	if options.ContentType != "" {
+		c.Header("Content-Type", options.ContentType)
+	}
+
+	disposition := "inline"
+	if options.Attachment {
+		disposition = "attachment"
+	}
+	if options.Name != "" {
+		// FormatMediaType encodes non-ASCII names as filename*
+		if value := mime.FormatMediaType(disposition, map[string]string{"filename": options.Name}); value != "" {
+			c.Header("Content-Disposition", value)
+			return
+		}
+	}
+	c.Header("Content-Disposition", disposition)
+}
+
+// detectContentType uses the extension, then sniffs the first 512 bytes
+func detectContentType(f io.ReadSeeker, name string) string {
+	if contentType := mime.TypeByExtension(filepath.Ext(name)); contentType != "" {
+		return contentType
+	}
+
+	var buf [512]byte
+	n, _ := io.ReadFull(f, buf[:])
+	f.Seek(0, io.SeekStart)
+	return http.DetectContentType(buf[:n])
+}
+
+func fileError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "file not found"})
+	case errors.Is(err, fs.ErrPermission):
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "file not accessible"})
+	default:
+		RequestLogger(c).Errorf("File error: %v", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+	}
+}
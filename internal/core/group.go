@@ -0,0 +1,30 @@
+package core
+
+import "fmt"
+
+// Contribute adds value to a named group, so a subsystem can collect
+// contributions from every module, e.g. Contribute[HealthIndicator](c,
+// "health", indicator). Contribute from Configure; consumers collect from
+// OnInit or later.
+func Contribute[T any](c *Container, group string, value T) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.groups[group] = append(c.groups[group], value)
+}
+
+// Group returns the values contributed to group, in contribution order
+func Group[T any](c *Container, group string) ([]T, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	members := c.groups[group]
+	values := make([]T, 0, len(members))
+	for _, member := range members {
+		value, ok := member.(T)
+		if !ok {
+			return nil, fmt.Errorf("%w: group %s contains %T, want %v", ErrDependencyType, group, member, typeOf[T]())
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
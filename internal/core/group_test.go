@@ -0,0 +1,29 @@
+package core
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestGroupKeepsContributionOrder(t *testing.T) {
+	c := NewContainer()
+	Contribute(c, "names", "users")
+	Contribute(c, "names", "orders")
+	Contribute(c, "ports", 8080)
+
+	names, err := Group[string](c, "names")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"users", "orders"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Group(names) = %v, want %v", names, want)
+	}
+
+	if empty, err := Group[string](c, "unknown"); err != nil || len(empty) != 0 {
+		t.Errorf("Group(unknown) = %v, %v; want an empty group", empty, err)
+	}
+	if _, err := Group[string](c, "ports"); !errors.Is(err, ErrDependencyType) {
+		t.Errorf("Group[string](ports) error = %v, want ErrDependencyType", err)
+	}
+}
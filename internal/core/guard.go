@@ -0,0 +1,154 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/fx"
+)
+
+const (
+	globalGuardsGroup     = "goblin.global_guards"
+	globalMiddlewareGroup = "goblin.global_middleware"
+)
+
+// Guard decides whether a request may reach its handler
+type Guard interface {
+	CanActivate(c *gin.Context) (bool, error)
+}
+
+type GuardFunc func(c *gin.Context) (bool, error)
+
+func (f GuardFunc) CanActivate(c *gin.Context) (bool, error) {
+	return f(c)
+}
+
+// GuardError lets a guard reject a request with a specific status and
+// message instead of the default 403.
+type GuardError struct {
+	Status    int
+	Message   string
+	Challenge string // WWW-Authenticate value sent with 401 responses
+}
+
+var (
+	ErrUnauthorized = NewGuardError(http.StatusUnauthorized, "Unauthorized")
+	ErrForbidden    = NewGuardError(http.StatusForbidden, "Forbidden")
+)
+
+func NewGuardError(status int, message string) *GuardError {
+	return &GuardError{Status: status, Message: message}
+}
+
+// Unauthorized returns a 401 error asking the client to authenticate
+// using the given scheme, e.g. `Bearer realm="api"`.
+func Unauthorized(challenge string) *GuardError {
+	return &GuardError{
+		Status:    http.StatusUnauthorized,
+		Message:   "Unauthorized",
+		Challenge: challenge,
+	}
+}
+
+func (e *GuardError) Error() string {
+	return e.Message
+}
+
+// RedirectError makes a guard redirect instead of rejecting, e.g. sending
+// browser routes to a login page.
+type RedirectError struct {
+	Status   int
+	Location string
+}
+
+func Redirect(location string) *RedirectError {
+	return &RedirectError{Status: http.StatusFound, Location: location}
+}
+
+func (e *RedirectError) Error() string {
+	return "redirect to " + e.Location
+}
+
+// GuardToMiddleware runs guard before the rest of the chain. A denied
+// request is answered according to the guard's result:
+//
+//	false, nil          403 Forbidden
+//	*GuardError         its Status and Message (401 adds WWW-Authenticate)
+//	*RedirectError      a redirect to Location
+//	any other error     403 Forbidden, with the error logged
+func GuardToMiddleware(guard Guard) gin.HandlerFunc {
+	stage := fmt.Sprintf("guard:%T", guard)
+	return func(c *gin.Context) {
+		end := BeginStage(c, stage)
+		allowed, err := guard.CanActivate(c)
+		end()
+		if err == nil && allowed {
+			c.Next()
+			return
+		}
+
+		// The guard already answered the request itself
+		if c.Writer.Written() {
+			c.Abort()
+			return
+		}
+
+		var redirect *RedirectError
+		if errors.As(err, &redirect) {
+			c.Redirect(redirect.Status, redirect.Location)
+			c.Abort()
+			return
+		}
+
+		guardErr := ErrForbidden
+		if !errors.As(err, &guardErr) && err != nil {
+			RequestLogger(c).Errorf("Guard error: %v", err)
+		}
+		AbortWithGuardError(c, guardErr)
+	}
+}
+
+// AbortWithGuardError writes the response for a guard rejection
+func AbortWithGuardError(c *gin.Context, err *GuardError) {
+	if err.Status == http.StatusUnauthorized && err.Challenge != "" {
+		c.Header("WWW-Authenticate", err.Challenge)
+	}
+	c.AbortWithStatusJSON(err.Status, gin.H{"error": err.Message})
+}
+
+// MiddlewareToGuard turns a check-style middleware into a guard. The
+// request is allowed when the middleware does not abort. The middleware
+// must not call c.Next itself.
+func MiddlewareToGuard(fn gin.HandlerFunc) Guard {
+	return GuardFunc(func(c *gin.Context) (bool, error) {
+		fn(c)
+		return !c.IsAborted(), nil
+	})
+}
+
+// ProvideGlobalGuard provides a guard that is applied to every route. The
+// constructor's result must implement Guard.
+func ProvideGlobalGuard(constructor interface{}) fx.Option {
+	return fx.Provide(fx.Annotate(constructor,
+		fx.As(new(Guard)),
+		fx.ResultTags(`group:"`+globalGuardsGroup+`"`),
+	))
+}
+
+// ProvideGlobalMiddleware provides middleware that is applied to every
+// route. The constructor must return a gin.HandlerFunc.
+func ProvideGlobalMiddleware(constructor interface{}) fx.Option {
+	return fx.Provide(fx.Annotate(constructor,
+		fx.ResultTags(`group:"`+globalMiddlewareGroup+`"`),
+	))
+}
+
+// globalHandlers collects the guards and middleware provided by modules
+type globalHandlers struct {
+	fx.In
+
+	Guards     []Guard           `group:"goblin.global_guards"`
+	Middleware []gin.HandlerFunc `group:"goblin.global_middleware"`
+}
@@ -0,0 +1,121 @@
+package core
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Hook points emitted by the framework, and by modules that cache or query
+// a database, for observability modules to subscribe to
+const (
+	HookRequestStarted  = "request.started"
+	HookRequestFinished = "request.finished"
+	HookHandlerError    = "handler.error"
+	HookCacheHit        = "cache.hit"
+	HookCacheMiss       = "cache.miss"
+	HookDBQuery         = "db.query"
+)
+
+type HookEvent struct {
+	Name     string
+	Context  *gin.Context  // Set for request events
+	Duration time.Duration // Set for request.finished and timed operations
+	Err      error
+	Attrs    map[string]interface{} // e.g. "key" for cache events, "query" for db.query
+}
+
+type Hook func(event HookEvent)
+
+// Hooks is a registry of internal hook points. Unlike an application event
+// bus it carries instrumentation only: hooks run synchronously on the
+// emitting goroutine and must be quick.
+type Hooks struct {
+	mu          sync.RWMutex
+	subscribers map[string][]*Hook
+	log         *Logger // set by the application
+}
+
+func NewHooks() *Hooks {
+	return &Hooks{subscribers: make(map[string][]*Hook)}
+}
+
+// On subscribes hook to the named hook point and returns a function that
+// unsubscribes it
+func (h *Hooks) On(name string, hook Hook) func() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	ref := &hook
+	h.subscribers[name] = append(h.subscribers[name], ref)
+
+	return func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+
+		subscribers := h.subscribers[name]
+		for i, candidate := range subscribers {
+			if candidate == ref {
+				h.subscribers[name] = append(subscribers[:i:i], subscribers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// Has reports whether anything listens to name, so emitters can skip
+// building events nobody reads
+func (h *Hooks) Has(name string) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.subscribers[name]) > 0
+}
+
+// Emit calls the subscribers of event.Name. A panicking subscriber is
+// logged and does not affect the others or the emitter.
+func (h *Hooks) Emit(event HookEvent) {
+	h.mu.RLock()
+	subscribers := h.subscribers[event.Name]
+	h.mu.RUnlock()
+
+	for _, hook := range subscribers {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					h.logger().Errorf("Hook %s panic: %v", event.Name, r)
+				}
+			}()
+			(*hook)(event)
+		}()
+	}
+}
+
+func (h *Hooks) logger() *Logger {
+	if h.log == nil {
+		return NewLogger(LevelInfo)
+	}
+	return h.log
+}
+
+// Middleware emits the request hook points
+func (h *Hooks) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if h.Has(HookRequestStarted) {
+			h.Emit(HookEvent{Name: HookRequestStarted, Context: c})
+		}
+
+		start := time.Now()
+		c.Next()
+		duration := time.Since(start)
+
+		if h.Has(HookHandlerError) {
+			for _, err := range c.Errors {
+				h.Emit(HookEvent{Name: HookHandlerError, Context: c, Err: err.Err})
+			}
+		}
+		if h.Has(HookRequestFinished) {
+			h.Emit(HookEvent{Name: HookRequestFinished, Context: c, Duration: duration})
+		}
+	}
+}
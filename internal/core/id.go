@@ -0,0 +1,177 @@
+package core
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// IDGenerator produces unique string identifiers
+type IDGenerator interface {
+	NewID() string
+}
+
+// IDGeneratorFunc adapts a function to IDGenerator
+type IDGeneratorFunc func() string
+
+func (f IDGeneratorFunc) NewID() string {
+	return f()
+}
+
+// ResolveIDGenerator returns the generator bound in the container, or a
+// UUIDv7 generator
+func ResolveIDGenerator(c *Container) IDGenerator {
+	if generator, err := Resolve[IDGenerator](c); err == nil {
+		return generator
+	}
+	return NewUUIDv7Generator(nil)
+}
+
+type uuidV7Generator struct {
+	clock Clock
+}
+
+// NewUUIDv7Generator returns time-ordered RFC 9562 version 7 UUIDs. A nil
+// clock uses the system clock.
+func NewUUIDv7Generator(clock Clock) IDGenerator {
+	if clock == nil {
+		clock = NewSystemClock(nil)
+	}
+	return uuidV7Generator{clock: clock}
+}
+
+func (g uuidV7Generator) NewID() string {
+	var id [16]byte
+	rand.Read(id[6:])
+	putMillis(id[:6], g.clock.Now())
+	id[6] = id[6]&0x0f | 0x70 // version 7
+	id[8] = id[8]&0x3f | 0x80 // RFC 9562 variant
+
+	buf := make([]byte, 36)
+	hex.Encode(buf[0:8], id[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], id[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], id[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], id[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], id[10:])
+	return string(buf)
+}
+
+type ulidGenerator struct {
+	clock Clock
+}
+
+// NewULIDGenerator returns lexically sortable ULIDs. A nil clock uses the
+// system clock.
+func NewULIDGenerator(clock Clock) IDGenerator {
+	if clock == nil {
+		clock = NewSystemClock(nil)
+	}
+	return ulidGenerator{clock: clock}
+}
+
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+func (g ulidGenerator) NewID() string {
+	var id [16]byte
+	rand.Read(id[6:])
+	putMillis(id[:6], g.clock.Now())
+
+	// 128 bits as 26 base32 characters, the first carrying 3 bits
+	out := make([]byte, 26)
+	var acc uint32
+	bits := 2 // pad to 130 bits
+	n := 0
+	for _, b := range id {
+		acc = acc<<8 | uint32(b)
+		bits += 8
+		for bits >= 5 {
+			bits -= 5
+			out[n] = crockford[acc>>bits&0x1f]
+			n++
+		}
+	}
+	return string(out)
+}
+
+func putMillis(dst []byte, t time.Time) {
+	ms := uint64(t.UnixMilli())
+	for i := 5; i >= 0; i-- {
+		dst[i] = byte(ms)
+		ms >>= 8
+	}
+}
+
+// SnowflakeEpoch is the start of Snowflake timestamps
+var SnowflakeEpoch = time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+const (
+	snowflakeNodeBits     = 10
+	snowflakeSequenceBits = 12
+	snowflakeMaxNode      = 1<<snowflakeNodeBits - 1
+	snowflakeMaxSequence  = 1<<snowflakeSequenceBits - 1
+)
+
+// Snowflake produces 63-bit, time-ordered numeric IDs made of milliseconds
+// since SnowflakeEpoch, a node number and a per-millisecond sequence
+type Snowflake struct {
+	mu       sync.Mutex
+	clock    Clock
+	node     int64
+	last     int64
+	sequence int64
+}
+
+// NewSnowflake returns a generator for node, which must be unique among the
+// processes sharing an ID space. A nil clock uses the system clock.
+func NewSnowflake(node int64, clock Clock) (*Snowflake, error) {
+	if node < 0 || node > snowflakeMaxNode {
+		return nil, fmt.Errorf("snowflake node must be between 0 and %d, got %d", snowflakeMaxNode, node)
+	}
+	if clock == nil {
+		clock = NewSystemClock(nil)
+	}
+	return &Snowflake{clock: clock, node: node}, nil
+}
+
+// Next returns the next ID. IDs keep increasing when the clock stalls or
+// steps backwards, or when a millisecond's sequence runs out.
+func (s *Snowflake) Next() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock.Now().Sub(SnowflakeEpoch).Milliseconds()
+	if now > s.last {
+		s.last = now
+		s.sequence = 0
+	} else if s.sequence++; s.sequence > snowflakeMaxSequence {
+		s.last++
+		s.sequence = 0
+	}
+	return uint64(s.last<<(snowflakeNodeBits+snowflakeSequenceBits) | s.node<<snowflakeSequenceBits | s.sequence)
+}
+
+func (s *Snowflake) NewID() string {
+	return strconv.FormatUint(s.Next(), 10)
+}
+
+// SequenceIDGenerator returns prefix1, prefix2, ... for deterministic fixtures
+type SequenceIDGenerator struct {
+	prefix string
+	next   atomic.Uint64
+}
+
+func NewSequenceIDGenerator(prefix string) *SequenceIDGenerator {
+	return &SequenceIDGenerator{prefix: prefix}
+}
+
+func (g *SequenceIDGenerator) NewID() string {
+	return g.prefix + strconv.FormatUint(g.next.Add(1), 10)
+}
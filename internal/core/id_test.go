@@ -0,0 +1,109 @@
+package core
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSnowflakeIsMonotonic(t *testing.T) {
+	clock := NewFakeClock(SnowflakeEpoch.Add(time.Hour))
+	snowflake, err := NewSnowflake(7, clock)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var last uint64
+	next := func(step string) {
+		t.Helper()
+		id := snowflake.Next()
+		if id <= last {
+			t.Fatalf("%s: id %d after %d", step, id, last)
+		}
+		if node := id >> snowflakeSequenceBits & snowflakeMaxNode; node != 7 {
+			t.Fatalf("%s: id %d carries node %d, want 7", step, id, node)
+		}
+		last = id
+	}
+
+	// More IDs than a millisecond's sequence holds, on a stalled clock
+	for i := 0; i < 3*(snowflakeMaxSequence+1); i++ {
+		next("stalled clock")
+	}
+	clock.Advance(time.Millisecond)
+	next("advanced clock")
+	clock.Set(SnowflakeEpoch.Add(time.Minute))
+	next("clock stepped backwards")
+}
+
+func TestSnowflakeIsUniqueAcrossGoroutines(t *testing.T) {
+	snowflake, err := NewSnowflake(1, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const goroutines, perGoroutine = 8, 2000
+	ids := make([][]uint64, goroutines)
+	var wg sync.WaitGroup
+	for g := range ids {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < perGoroutine; i++ {
+				ids[g] = append(ids[g], snowflake.Next())
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	seen := make(map[uint64]bool, goroutines*perGoroutine)
+	for _, list := range ids {
+		for i, id := range list {
+			if i > 0 && id <= list[i-1] {
+				t.Fatalf("id %d after %d within one goroutine", id, list[i-1])
+			}
+			if seen[id] {
+				t.Fatalf("id %d generated twice", id)
+			}
+			seen[id] = true
+		}
+	}
+}
+
+func TestTimeOrderedIDsSortByMillisecond(t *testing.T) {
+	tests := []struct {
+		name   string
+		create func(Clock) IDGenerator
+		length int
+	}{
+		{"uuidv7", NewUUIDv7Generator, 36},
+		{"ulid", NewULIDGenerator, 26},
+	}
+	for _, tt := range tests {
+		clock := NewFakeClock(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+		generator := tt.create(clock)
+
+		last := ""
+		for i := 0; i < 100; i++ {
+			id := generator.NewID()
+			if len(id) != tt.length {
+				t.Fatalf("%s: %q has length %d, want %d", tt.name, id, len(id), tt.length)
+			}
+			if id <= last {
+				t.Fatalf("%s: %q does not sort after %q", tt.name, id, last)
+			}
+			last = id
+			clock.Advance(time.Millisecond)
+		}
+	}
+}
+
+func TestUUIDv7VersionAndVariant(t *testing.T) {
+	id := NewUUIDv7Generator(nil).NewID()
+	if id[14] != '7' {
+		t.Errorf("%s: version %c, want 7", id, id[14])
+	}
+	if v := id[19]; v != '8' && v != '9' && v != 'a' && v != 'b' {
+		t.Errorf("%s: variant nibble %c, want 8, 9, a or b", id, v)
+	}
+}
@@ -0,0 +1,139 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	ErrRouteNotFound  = errors.New("route not found")
+	ErrAmbiguousRoute = errors.New("route name is ambiguous")
+	ErrRouteParams    = errors.New("route parameter count mismatch")
+)
+
+const linksKey = "goblin.links"
+
+// Links maps handler names such as "user.Controller.GetUser" to the route
+// templates they were registered under, so URLs can be built from names
+// instead of hardcoded paths.
+type Links struct {
+	mu     sync.RWMutex
+	routes map[string]string
+}
+
+func NewLinks() *Links {
+	return &Links{
+		routes: make(map[string]string),
+	}
+}
+
+// Index rebuilds the name table from the registered routes. When a handler
+// is mounted on several routes the GET route wins.
+func (l *Links) Index(routes gin.RoutesInfo) {
+	table := make(map[string]string, len(routes))
+	for _, route := range routes {
+		name := routeName(route.Handler)
+		if _, exists := table[name]; exists && route.Method != http.MethodGet {
+			continue
+		}
+		table[name] = route.Path
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.routes = table
+}
+
+// Template returns the route template for a handler name. The name may be
+// fully qualified ("user.Controller.GetUser") or a unique suffix of one
+// ("Controller.GetUser").
+func (l *Links) Template(name string) (string, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if path, exists := l.routes[name]; exists {
+		return path, nil
+	}
+
+	var match string
+	found := false
+	for key, path := range l.routes {
+		if strings.HasSuffix(key, "."+name) {
+			if found {
+				return "", fmt.Errorf("%w: %s", ErrAmbiguousRoute, name)
+			}
+			match, found = path, true
+		}
+	}
+	if !found {
+		return "", fmt.Errorf("%w: %s", ErrRouteNotFound, name)
+	}
+	return match, nil
+}
+
+// URL builds a path for the named handler, filling the template's
+// parameters in order.
+func (l *Links) URL(name string, params ...interface{}) (string, error) {
+	template, err := l.Template(name)
+	if err != nil {
+		return "", err
+	}
+
+	segments := strings.Split(template, "/")
+	next := 0
+	for i, segment := range segments {
+		if segment == "" || (segment[0] != ':' && segment[0] != '*') {
+			continue
+		}
+		if next >= len(params) {
+			return "", fmt.Errorf("%w: %s expects more than %d", ErrRouteParams, name, len(params))
+		}
+		value := fmt.Sprint(params[next])
+		if segment[0] == ':' {
+			value = url.PathEscape(value)
+		} else {
+			value = strings.TrimPrefix(value, "/")
+		}
+		segments[i] = value
+		next++
+	}
+	if next != len(params) {
+		return "", fmt.Errorf("%w: %s expects %d, got %d", ErrRouteParams, name, next, len(params))
+	}
+
+	return strings.Join(segments, "/"), nil
+}
+
+// RouteURL builds a path for the named handler using the application the
+// request is being served by.
+func RouteURL(c *gin.Context, name string, params ...interface{}) (string, error) {
+	value, exists := c.Get(linksKey)
+	if !exists {
+		return "", fmt.Errorf("%w: %s", ErrRouteNotFound, name)
+	}
+	return value.(*Links).URL(name, params...)
+}
+
+// middleware exposes the links to handlers through RouteURL
+func (l *Links) middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(linksKey, l)
+		c.Next()
+	}
+}
+
+// routeName turns a gin handler name such as
+// "github.com/acme/app/user.(*Controller).GetUser-fm" into "user.Controller.GetUser".
+func routeName(handler string) string {
+	if i := strings.LastIndex(handler, "/"); i >= 0 {
+		handler = handler[i+1:]
+	}
+	handler = strings.TrimSuffix(handler, "-fm")
+	return strings.NewReplacer("(*", "", "(", "", ")", "").Replace(handler)
+}
@@ -0,0 +1,117 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+)
+
+var ErrUnknownLogLevel = errors.New("unknown log level")
+
+// LogLevel orders log lines by severity; the zero value is LevelInfo
+type LogLevel int32
+
+const (
+	LevelDebug LogLevel = iota - 1
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+func (l LogLevel) String() string {
+	switch l {
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelWarn:
+		return "warn"
+	case LevelError:
+		return "error"
+	}
+	return fmt.Sprintf("level(%d)", int32(l))
+}
+
+// ParseLogLevel reads "debug", "info", "warn" or "error", ignoring case
+func ParseLogLevel(name string) (LogLevel, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "debug":
+		return LevelDebug, nil
+	case "info":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	case "error":
+		return LevelError, nil
+	}
+	return LevelInfo, fmt.Errorf("%w: %q", ErrUnknownLogLevel, name)
+}
+
+// Logger writes leveled lines through the standard log package. The
+// application and its middleware log through one Logger, whose level can
+// be changed while running, e.g. from the admin module.
+type Logger struct {
+	level atomic.Int32
+}
+
+func NewLogger(level LogLevel) *Logger {
+	l := &Logger{}
+	l.SetLevel(level)
+	return l
+}
+
+// ResolveLogger returns the logger bound in the container, or one logging
+// at LevelInfo
+func ResolveLogger(c *Container) *Logger {
+	if logger, err := Resolve[*Logger](c); err == nil {
+		return logger
+	}
+	return NewLogger(LevelInfo)
+}
+
+// RequestLogger returns the logger of the application serving c
+func RequestLogger(c *gin.Context) *Logger {
+	if container := RequestContainer(c); container != nil {
+		return ResolveLogger(container)
+	}
+	return NewLogger(LevelInfo)
+}
+
+func (l *Logger) Level() LogLevel {
+	return LogLevel(l.level.Load())
+}
+
+func (l *Logger) SetLevel(level LogLevel) {
+	l.level.Store(int32(level))
+}
+
+// Enabled reports whether lines at level are written
+func (l *Logger) Enabled(level LogLevel) bool {
+	return level >= l.Level()
+}
+
+func (l *Logger) Debugf(format string, args ...interface{}) {
+	l.logf(LevelDebug, format, args...)
+}
+
+func (l *Logger) Infof(format string, args ...interface{}) {
+	l.logf(LevelInfo, format, args...)
+}
+
+func (l *Logger) Warnf(format string, args ...interface{}) {
+	l.logf(LevelWarn, format, args...)
+}
+
+func (l *Logger) Errorf(format string, args ...interface{}) {
+	l.logf(LevelError, format, args...)
+}
+
+func (l *Logger) logf(level LogLevel, format string, args ...interface{}) {
+	if l.Enabled(level) {
+		log.Output(3, fmt.Sprintf(format, args...))
+	}
+}
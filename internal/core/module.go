@@ -14,6 +14,18 @@ type RouteModule interface {
 	RegisterRoutes(router *gin.RouterGroup)
 }
 
+// PrefixedModule mounts all of its routes under RoutePrefix, e.g. "/billing"
+type PrefixedModule interface {
+	RouteModule
+	RoutePrefix() string
+}
+
+// MiddlewareModule runs Middleware on every route it registers
+type MiddlewareModule interface {
+	RouteModule
+	Middleware() []gin.HandlerFunc
+}
+
 type FxModule interface {
 	Module
 	ProvideDependencies() fx.Option
@@ -25,6 +37,40 @@ type LifecycleModule interface {
 	OnDestroy() error
 }
 
+// ConditionalModule is loaded only when its condition holds, e.g. an
+// in-memory cache module for dev and a Redis one for prod.
+type ConditionalModule interface {
+	Module
+	Condition() Condition
+}
+
+type Condition func(opts ApplicationOptions) bool
+
+// ConditionalOnProfile holds when the application runs in one of profiles
+func ConditionalOnProfile(profiles ...string) Condition {
+	return func(opts ApplicationOptions) bool {
+		return opts.InProfile(profiles...)
+	}
+}
+
+// ExceptInProfiles holds when the application runs in none of profiles
+func ExceptInProfiles(profiles ...string) Condition {
+	return func(opts ApplicationOptions) bool {
+		return !opts.InProfile(profiles...)
+	}
+}
+
+// UseIf returns handlers when condition holds for opts and nothing
+// otherwise, so the check happens once when routes are registered:
+//
+//	router.Use(core.UseIf(opts, core.ExceptInProfiles("test"), limiter.Middleware())...)
+func UseIf(opts ApplicationOptions, condition Condition, handlers ...gin.HandlerFunc) []gin.HandlerFunc {
+	if !condition(opts) {
+		return nil
+	}
+	return handlers
+}
+
 type BaseModule struct{}
 
 func (b *BaseModule) Configure(container *Container) {}
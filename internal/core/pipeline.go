@@ -0,0 +1,101 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RoutePipeline is the resolved handler chain of a route: global
+// middleware, module middleware, guards and the handler, in the order they
+// run
+type RoutePipeline struct {
+	Route    string   `json:"route"`
+	Handlers []string `json:"handlers"`
+}
+
+type pipelineProbeKey struct{}
+
+var funcSuffix = regexp.MustCompile(`(\.func\d+)+$`)
+
+// probeHandler runs first on every route. For the in-process requests sent
+// by Pipeline it records the chain and stops before anything else runs;
+// clients cannot trigger it since it is keyed on the request context.
+func probeHandler(c *gin.Context) {
+	probe, ok := c.Request.Context().Value(pipelineProbeKey{}).(*pipelineProbe)
+	if !ok {
+		c.Next()
+		return
+	}
+	probe.path = c.FullPath()
+	for _, name := range c.HandlerNames()[1:] {
+		probe.handlers = append(probe.handlers, handlerName(name))
+	}
+	c.AbortWithStatus(http.StatusNoContent)
+}
+
+type pipelineProbe struct {
+	path     string
+	handlers []string
+}
+
+// handlerName shortens a function name such as
+// "github.com/acme/app/middleware.Logger.func1" to "middleware.Logger"
+func handlerName(name string) string {
+	return funcSuffix.ReplaceAllString(routeName(name), "")
+}
+
+// Pipeline resolves the handler chain of a registered route, given as
+// "METHOD /path/:param". The routes must be registered, i.e. the
+// application started with Run or Handler.
+func (app *Application) Pipeline(route string) (RoutePipeline, error) {
+	method, template, found := strings.Cut(route, " ")
+	if !found {
+		return RoutePipeline{}, fmt.Errorf("route %q: want \"METHOD /path\"", route)
+	}
+
+	// Fill in parameters so the probe matches the template
+	segments := strings.Split(template, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			segments[i] = "_"
+		}
+	}
+
+	probe := &pipelineProbe{}
+	ctx := context.WithValue(context.Background(), pipelineProbeKey{}, probe)
+	req := httptest.NewRequest(method, strings.Join(segments, "/"), nil).WithContext(ctx)
+	app.engine.ServeHTTP(httptest.NewRecorder(), req)
+
+	if probe.path != template {
+		return RoutePipeline{}, fmt.Errorf("%w: %s", ErrRouteNotFound, route)
+	}
+	return RoutePipeline{Route: method + " " + template, Handlers: probe.handlers}, nil
+}
+
+// Pipelines resolves the handler chain of every registered route
+func (app *Application) Pipelines() ([]RoutePipeline, error) {
+	routes := app.engine.Routes()
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		return routes[i].Method < routes[j].Method
+	})
+
+	pipelines := make([]RoutePipeline, 0, len(routes))
+	for _, route := range routes {
+		pipeline, err := app.Pipeline(route.Method + " " + route.Path)
+		if err != nil {
+			return nil, err
+		}
+		pipelines = append(pipelines, pipeline)
+	}
+	return pipelines, nil
+}
@@ -0,0 +1,274 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	timelineKey = "goblin.timeline"
+
+	StageMiddleware = "middleware"
+	StageHandler    = "handler"
+	StageWrite      = "write"
+)
+
+// StageStats aggregates the time one pipeline stage took across requests
+type StageStats struct {
+	Count   int64   `json:"count"`
+	TotalMs float64 `json:"total_ms"`
+	MeanMs  float64 `json:"mean_ms"`
+	MaxMs   float64 `json:"max_ms"`
+}
+
+// RouteProfile is the per-stage timing of one route
+type RouteProfile struct {
+	Route    string                `json:"route"`
+	Requests int64                 `json:"requests"`
+	Stages   map[string]StageStats `json:"stages"`
+}
+
+// Profiler records how long each stage of the request pipeline takes:
+// middleware, each guard, the handler and writing the response. Stage
+// times are exclusive, so they add up to the request time.
+type Profiler struct {
+	mu     sync.Mutex
+	routes map[string]*routeStats
+}
+
+type routeStats struct {
+	requests int64
+	stages   map[string]*stageStats
+}
+
+type stageStats struct {
+	count int64
+	total time.Duration
+	max   time.Duration
+}
+
+func NewProfiler() *Profiler {
+	return &Profiler{routes: make(map[string]*routeStats)}
+}
+
+// Middleware starts the request timeline. In debug mode it also reports the
+// stages finished before the response is written in a Server-Timing header.
+func (p *Profiler) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		timeline := &timeline{start: time.Now(), stages: make(map[string]time.Duration)}
+		root := timeline.begin(StageMiddleware)
+		c.Set(timelineKey, timeline)
+		c.Writer = &timedWriter{ResponseWriter: c.Writer, timeline: timeline, serverTiming: gin.IsDebugging()}
+
+		c.Next()
+
+		timeline.end(root)
+		route := c.FullPath()
+		if route == "" {
+			route = "(unmatched)"
+		}
+		p.record(c.Request.Method+" "+route, timeline)
+	}
+}
+
+func (p *Profiler) record(route string, timeline *timeline) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stats, exists := p.routes[route]
+	if !exists {
+		stats = &routeStats{stages: make(map[string]*stageStats)}
+		p.routes[route] = stats
+	}
+	stats.requests++
+
+	for name, duration := range timeline.stages {
+		stage, exists := stats.stages[name]
+		if !exists {
+			stage = &stageStats{}
+			stats.stages[name] = stage
+		}
+		stage.count++
+		stage.total += duration
+		if duration > stage.max {
+			stage.max = duration
+		}
+	}
+}
+
+// Snapshot returns the aggregates of every route seen so far
+func (p *Profiler) Snapshot() []RouteProfile {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	profiles := make([]RouteProfile, 0, len(p.routes))
+	for route, stats := range p.routes {
+		profile := RouteProfile{
+			Route:    route,
+			Requests: stats.requests,
+			Stages:   make(map[string]StageStats, len(stats.stages)),
+		}
+		for name, stage := range stats.stages {
+			profile.Stages[name] = StageStats{
+				Count:   stage.count,
+				TotalMs: milliseconds(stage.total),
+				MeanMs:  milliseconds(stage.total / time.Duration(stage.count)),
+				MaxMs:   milliseconds(stage.max),
+			}
+		}
+		profiles = append(profiles, profile)
+	}
+
+	sort.Slice(profiles, func(i, j int) bool {
+		return profiles[i].Route < profiles[j].Route
+	})
+	return profiles
+}
+
+// Reset drops the aggregates
+func (p *Profiler) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.routes = make(map[string]*routeStats)
+}
+
+// BeginStage starts timing a named stage of the current request and returns
+// the function that ends it. It does nothing when profiling is off.
+func BeginStage(c *gin.Context, name string) func() {
+	value, _ := c.Get(timelineKey)
+	timeline, ok := value.(*timeline)
+	if !ok {
+		return func() {}
+	}
+	frame := timeline.begin(name)
+	return func() { timeline.end(frame) }
+}
+
+// StageTimings returns the stages of the current request finished so far,
+// or nil when profiling is off
+func StageTimings(c *gin.Context) map[string]time.Duration {
+	value, _ := c.Get(timelineKey)
+	timeline, ok := value.(*timeline)
+	if !ok {
+		return nil
+	}
+
+	timeline.mu.Lock()
+	defer timeline.mu.Unlock()
+
+	timings := make(map[string]time.Duration, len(timeline.stages))
+	for name, duration := range timeline.stages {
+		timings[name] = duration
+	}
+	return timings
+}
+
+// profileHandler times everything after the module middleware as the
+// handler stage
+func profileHandler(c *gin.Context) {
+	end := BeginStage(c, StageHandler)
+	defer end()
+	c.Next()
+}
+
+// timeline keeps the stages of one request. Stages nest, and each one is
+// charged only for the time not spent in the stages inside it.
+type timeline struct {
+	mu     sync.Mutex
+	start  time.Time
+	stack  []*frame
+	stages map[string]time.Duration
+}
+
+type frame struct {
+	name     string
+	start    time.Time
+	children time.Duration
+}
+
+func (t *timeline) begin(name string) *frame {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	f := &frame{name: name, start: time.Now()}
+	t.stack = append(t.stack, f)
+	return f
+}
+
+func (t *timeline) end(f *frame) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// Frames end innermost first; find f in case a stage ended out of order
+	i := len(t.stack) - 1
+	for i >= 0 && t.stack[i] != f {
+		i--
+	}
+	if i < 0 {
+		return
+	}
+	t.stack = t.stack[:i]
+
+	inclusive := time.Since(f.start)
+	t.stages[f.name] += inclusive - f.children
+	if i > 0 {
+		t.stack[i-1].children += inclusive
+	}
+}
+
+// serverTiming formats the finished stages as a Server-Timing header value
+func (t *timeline) serverTiming() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	names := make([]string, 0, len(t.stages))
+	for name := range t.stages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	entries := make([]string, 0, len(names)+1)
+	for _, name := range names {
+		token := strings.NewReplacer(":", "-", " ", "-", "*", "").Replace(name)
+		entries = append(entries, fmt.Sprintf("%s;dur=%.3f", token, milliseconds(t.stages[name])))
+	}
+	entries = append(entries, fmt.Sprintf("total;dur=%.3f", milliseconds(time.Since(t.start))))
+	return strings.Join(entries, ", ")
+}
+
+// timedWriter charges response writes to the write stage
+type timedWriter struct {
+	gin.ResponseWriter
+	timeline     *timeline
+	serverTiming bool
+}
+
+func (w *timedWriter) beforeWrite() {
+	if w.serverTiming && !w.Written() {
+		w.Header().Set("Server-Timing", w.timeline.serverTiming())
+	}
+}
+
+func (w *timedWriter) WriteHeaderNow() {
+	w.beforeWrite()
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *timedWriter) Write(data []byte) (int, error) {
+	w.beforeWrite()
+	f := w.timeline.begin(StageWrite)
+	defer w.timeline.end(f)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *timedWriter) WriteString(s string) (int, error) {
+	w.beforeWrite()
+	f := w.timeline.begin(StageWrite)
+	defer w.timeline.end(f)
+	return w.ResponseWriter.WriteString(s)
+}
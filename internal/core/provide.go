@@ -0,0 +1,99 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+var ErrInvalidConstructor = errors.New("invalid constructor")
+
+var (
+	errorType        = reflect.TypeOf((*error)(nil)).Elem()
+	cleanupType      = reflect.TypeOf(func() {})
+	errorCleanupType = reflect.TypeOf(func() error { return nil })
+)
+
+// Provide calls constructor with its arguments resolved from the container
+// and binds the first result under its declared type. Constructors may
+// return T, (T, error), (T, func(), error) or (T, func() error, error),
+// following the usual Go and fx conventions; cleanup functions run on
+// Dispose.
+func (c *Container) Provide(constructor interface{}) error {
+	fn := reflect.ValueOf(constructor)
+	typ := fn.Type()
+	if typ.Kind() != reflect.Func {
+		return fmt.Errorf("%w: %T is not a function", ErrInvalidConstructor, constructor)
+	}
+	if err := checkConstructorResults(typ); err != nil {
+		return fmt.Errorf("%w: %v: %v", ErrInvalidConstructor, typ, err)
+	}
+
+	args := make([]reflect.Value, typ.NumIn())
+	for i := range args {
+		dependency, err := c.Resolve(typ.In(i))
+		if err != nil {
+			return fmt.Errorf("%v: argument %d: %w: %v", typ, i, err, typ.In(i))
+		}
+		if dependency == nil {
+			args[i] = reflect.Zero(typ.In(i))
+			continue
+		}
+		args[i] = reflect.ValueOf(dependency)
+	}
+
+	results := fn.Call(args)
+	if last := results[len(results)-1]; len(results) > 1 && !last.IsNil() {
+		return fmt.Errorf("%v: %w", typ.Out(0), last.Interface().(error))
+	}
+
+	c.Bind(typ.Out(0), results[0].Interface())
+	if len(results) == 3 && !results[1].IsNil() {
+		c.mutex.Lock()
+		c.cleanups = append(c.cleanups, results[1].Interface())
+		c.mutex.Unlock()
+	}
+	return nil
+}
+
+func checkConstructorResults(typ reflect.Type) error {
+	switch typ.NumOut() {
+	case 1:
+		return nil
+	case 2:
+		if typ.Out(1) != errorType {
+			return errors.New("second result must be error")
+		}
+		return nil
+	case 3:
+		if cleanup := typ.Out(1); cleanup != cleanupType && cleanup != errorCleanupType {
+			return errors.New("second result must be func() or func() error")
+		}
+		if typ.Out(2) != errorType {
+			return errors.New("third result must be error")
+		}
+		return nil
+	}
+	return errors.New("must return T, (T, error) or (T, func(), error)")
+}
+
+// Dispose runs the cleanup functions of provided dependencies, newest first
+func (c *Container) Dispose() error {
+	c.mutex.Lock()
+	cleanups := c.cleanups
+	c.cleanups = nil
+	c.mutex.Unlock()
+
+	errs := make([]error, 0)
+	for i := len(cleanups) - 1; i >= 0; i-- {
+		switch cleanup := cleanups[i].(type) {
+		case func():
+			cleanup()
+		case func() error:
+			if err := cleanup(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
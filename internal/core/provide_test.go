@@ -0,0 +1,75 @@
+package core
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type database struct {
+	dsn string
+}
+
+type userStore struct {
+	db *database
+}
+
+func TestProvideResolvesArgumentsAndRunsCleanups(t *testing.T) {
+	c := NewContainer()
+	Bind(c, "postgres://localhost")
+
+	var closed []string
+	if err := c.Provide(func(dsn string) (*database, func(), error) {
+		return &database{dsn: dsn}, func() { closed = append(closed, "database") }, nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Provide(func(db *database) (*userStore, func() error, error) {
+		return &userStore{db: db}, func() error {
+			closed = append(closed, "users")
+			return errors.New("flush failed")
+		}, nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	store := MustResolve[*userStore](c)
+	if store.db.dsn != "postgres://localhost" {
+		t.Errorf("store built on %q, want the bound DSN", store.db.dsn)
+	}
+
+	if err := c.Dispose(); err == nil || err.Error() != "flush failed" {
+		t.Errorf("Dispose() = %v, want the cleanup's error", err)
+	}
+	if want := []string{"users", "database"}; !reflect.DeepEqual(closed, want) {
+		t.Errorf("cleanups ran as %v, want newest first %v", closed, want)
+	}
+	if err := c.Dispose(); err != nil || len(closed) != 2 {
+		t.Errorf("second Dispose() = %v and ran %d cleanups, want nothing run again", err, len(closed))
+	}
+}
+
+func TestProvideErrors(t *testing.T) {
+	failure := errors.New("connection refused")
+	tests := []struct {
+		name        string
+		constructor interface{}
+		err         error
+	}{
+		{"not a function", &database{}, ErrInvalidConstructor},
+		{"second result not an error", func() (*database, string) { return nil, "" }, ErrInvalidConstructor},
+		{"bad cleanup", func() (*database, func() int, error) { return nil, nil, nil }, ErrInvalidConstructor},
+		{"too many results", func() (*database, func(), error, error) { return nil, nil, nil, nil }, ErrInvalidConstructor},
+		{"unresolved argument", func(*database) *userStore { return nil }, ErrNotFound},
+		{"constructor error", func() (*database, error) { return nil, failure }, failure},
+	}
+	for _, tt := range tests {
+		c := NewContainer()
+		if err := c.Provide(tt.constructor); !errors.Is(err, tt.err) {
+			t.Errorf("%s: Provide() = %v, want %v", tt.name, err, tt.err)
+		}
+		if _, err := Resolve[*database](c); !errors.Is(err, ErrNotFound) {
+			t.Errorf("%s: a failed Provide bound *database", tt.name)
+		}
+	}
+}
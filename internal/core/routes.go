@@ -0,0 +1,139 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RouteConflict describes a route a module could not register
+type RouteConflict struct {
+	Module   string   // Module whose registration failed
+	Existing []string // Routes it collides with, as "METHOD path (module)"
+	Reason   string   // Why the routes cannot coexist
+}
+
+// RouteConflictError is returned at startup instead of letting gin panic
+// midway through route registration.
+type RouteConflictError struct {
+	Conflicts []RouteConflict
+}
+
+func (e *RouteConflictError) Error() string {
+	var b strings.Builder
+	b.WriteString("route conflicts detected:\n")
+
+	w := tabwriter.NewWriter(&b, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "MODULE\tCONFLICTS WITH\tREASON")
+	for _, conflict := range e.Conflicts {
+		existing := strings.Join(conflict.Existing, ", ")
+		if existing == "" {
+			existing = "(same module)"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", conflict.Module, existing, conflict.Reason)
+	}
+	w.Flush()
+
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// routeOwner records which module registered a route
+type routeOwner struct {
+	method string
+	path   string
+	module string
+}
+
+// moduleGroup is the router group a module registers its routes on. With
+// profiling on, the time after the module middleware is charged to the
+// handler stage.
+func moduleGroup(engine *gin.Engine, module RouteModule, profiling bool) *gin.RouterGroup {
+	prefix := ""
+	if prefixed, ok := module.(PrefixedModule); ok {
+		prefix = prefixed.RoutePrefix()
+	}
+
+	var handlers []gin.HandlerFunc
+	if withMiddleware, ok := module.(MiddlewareModule); ok {
+		handlers = withMiddleware.Middleware()
+	}
+	if profiling {
+		handlers = append(handlers, profileHandler)
+	}
+
+	return engine.Group(prefix, handlers...)
+}
+
+// registerModuleRoutes lets a module register its routes, turning gin's
+// registration panics into an error.
+func registerModuleRoutes(module RouteModule, router *gin.RouterGroup) (reason string) {
+	defer func() {
+		if err := recover(); err != nil {
+			reason = fmt.Sprint(err)
+		}
+	}()
+
+	module.RegisterRoutes(router)
+	return ""
+}
+
+// newRoutePath extracts the path of the route gin refused to register from
+// its panic message
+var newRoutePath = regexp.MustCompile(`(?:for path|in new path) '([^']*)'`)
+
+// conflictingOwners lists the registered routes the route named in a gin
+// registration panic collides with. The message does not carry the method,
+// so owners of every method are considered.
+func conflictingOwners(owners []routeOwner, reason string) []string {
+	match := newRoutePath.FindStringSubmatch(reason)
+	if match == nil {
+		return nil
+	}
+
+	existing := make([]string, 0)
+	for _, owner := range owners {
+		if routeConflict(owner.path, match[1]) != "" {
+			existing = append(existing, fmt.Sprintf("%s %s (%s)", owner.method, owner.path, owner.module))
+		}
+	}
+	return existing
+}
+
+// routeConflict reports why gin cannot register two routes of the same
+// method side by side, comparing whole segments: the same path twice, two
+// parameters with different names in one position, or a catch-all sharing
+// its position with any other segment, which it would shadow. A static
+// segment next to a parameter is fine; gin tries the static one first.
+func routeConflict(existing, path string) string {
+	if existing == path {
+		return "duplicate route"
+	}
+
+	existingSegments := strings.Split(existing, "/")
+	segments := strings.Split(path, "/")
+	for i := 0; i < len(segments) && i < len(existingSegments); i++ {
+		a, b := existingSegments[i], segments[i]
+		switch {
+		case strings.HasPrefix(a, "*") || strings.HasPrefix(b, "*"):
+			return fmt.Sprintf("catch-all wildcard shadows segment %q", pick(a, b, "*"))
+		case strings.HasPrefix(a, ":") && strings.HasPrefix(b, ":"):
+			if a != b {
+				return fmt.Sprintf("wildcards %s and %s in the same position", a, b)
+			}
+		case a != b:
+			return ""
+		}
+	}
+	return ""
+}
+
+// pick returns whichever of a and b does not start with prefix
+func pick(a, b, prefix string) string {
+	if strings.HasPrefix(a, prefix) {
+		return b
+	}
+	return a
+}
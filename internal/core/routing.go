@@ -0,0 +1,162 @@
+package core
+
+import (
+	"net/http"
+	"strings"
+)
+
+type TrailingSlashPolicy string
+
+const (
+	// TrailingSlashRedirect redirects /users/ to /users (gin's default)
+	TrailingSlashRedirect TrailingSlashPolicy = "redirect"
+	// TrailingSlashStrict answers 404 when the slash does not match the route
+	TrailingSlashStrict TrailingSlashPolicy = "strict"
+	// TrailingSlashIgnore serves /users/ as /users, and /users as /users/,
+	// without redirecting, when only the other form has a route
+	TrailingSlashIgnore TrailingSlashPolicy = "ignore"
+)
+
+// RoutingPolicy is applied uniformly to every route of the application
+type RoutingPolicy struct {
+	TrailingSlash   TrailingSlashPolicy
+	CaseInsensitive bool // Redirect /Users to /users when only the case differs
+}
+
+// applyRoutingPolicy configures the engine's own path handling
+func (app *Application) applyRoutingPolicy() {
+	policy := app.config.RoutingPolicy
+	app.engine.RedirectTrailingSlash = policy.TrailingSlash == TrailingSlashRedirect
+	app.engine.RedirectFixedPath = policy.CaseInsensitive
+}
+
+// handler returns the http.Handler requests are served by, wrapping the
+// engine with the parts of the routing policy gin cannot express.
+func (app *Application) handler() http.Handler {
+	var handler http.Handler = app.adapter
+
+	if app.config.AutoHead {
+		next := handler
+		handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodHead || app.hasHeadRoute(r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// net/http drops the body of HEAD responses and still computes
+			// Content-Length, so the GET route can serve the request as is
+			get := *r
+			get.Method = http.MethodGet
+			next.ServeHTTP(w, &get)
+		})
+	}
+
+	if app.config.RoutingPolicy.TrailingSlash == TrailingSlashIgnore {
+		next := handler
+		handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if path := r.URL.Path; path != "/" && !app.hasRoute(r.Method, path) {
+				other := path + "/"
+				if strings.HasSuffix(path, "/") {
+					other = "/" + strings.Trim(path, "/")
+				}
+				if app.hasRoute(r.Method, other) {
+					r.URL.Path = other
+					r.URL.RawPath = ""
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+
+	// Outermost, so the routing policy sees the overridden method
+	if app.config.MethodOverride {
+		next := handler
+		handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodPost {
+				switch method := strings.ToUpper(r.Header.Get("X-HTTP-Method-Override")); method {
+				case http.MethodPut, http.MethodPatch, http.MethodDelete:
+					r.Method = method
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+
+	return handler
+}
+
+// hasHeadRoute reports whether a HEAD route was registered explicitly for path
+func (app *Application) hasHeadRoute(path string) bool {
+	app.mu.RLock()
+	defer app.mu.RUnlock()
+
+	for _, template := range app.headRoutes {
+		if matchTemplate(template, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasRoute reports whether a route matches path as it is, trailing slash
+// included. HEAD requests also match GET routes, see AutoHead.
+func (app *Application) hasRoute(method, path string) bool {
+	app.mu.RLock()
+	defer app.mu.RUnlock()
+
+	for _, route := range app.routes {
+		routeMethod, template, _ := strings.Cut(route, " ")
+		if routeMethod != method && !(method == http.MethodHead && routeMethod == http.MethodGet && app.config.AutoHead) {
+			continue
+		}
+		if matchSlashTemplate(template, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchSlashTemplate is matchTemplate minding trailing slashes, so
+// "/admin/pprof/" matches "/admin/pprof/" but "/users" does not
+func matchSlashTemplate(template, path string) bool {
+	templateSegments := strings.Split(template, "/")
+	pathSegments := strings.Split(path, "/")
+
+	for i, segment := range templateSegments {
+		if i >= len(pathSegments) {
+			return false
+		}
+		if strings.HasPrefix(segment, "*") {
+			return true
+		}
+		if strings.HasPrefix(segment, ":") {
+			if pathSegments[i] == "" {
+				return false
+			}
+			continue
+		}
+		if segment != pathSegments[i] {
+			return false
+		}
+	}
+	return len(templateSegments) == len(pathSegments)
+}
+
+// matchTemplate reports whether path matches a gin route template
+func matchTemplate(template, path string) bool {
+	templateSegments := strings.Split(strings.Trim(template, "/"), "/")
+	pathSegments := strings.Split(strings.Trim(path, "/"), "/")
+
+	for i, segment := range templateSegments {
+		if strings.HasPrefix(segment, "*") {
+			return true
+		}
+		if i >= len(pathSegments) {
+			return false
+		}
+		if !strings.HasPrefix(segment, ":") && segment != pathSegments[i] {
+			return false
+		}
+	}
+	return len(templateSegments) == len(pathSegments)
+}
@@ -0,0 +1,72 @@
+package core
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+type slashModule struct {
+	BaseModule
+}
+
+func (m *slashModule) RegisterRoutes(router *gin.RouterGroup) {
+	reply := func(name string) gin.HandlerFunc {
+		return func(c *gin.Context) { c.String(http.StatusOK, name) }
+	}
+	router.GET("/users", reply("users"))
+	router.GET("/teams/", reply("teams/"))
+	router.GET("/both", reply("both"))
+	router.GET("/both/", reply("both/"))
+	router.PUT("/users/:id", reply("put user"))
+	router.GET("/static/*file", reply("static"))
+}
+
+func TestTrailingSlashIgnore(t *testing.T) {
+	module := NewTestingModule(&slashModule{}).WithOptions(
+		WithRoutingPolicy(RoutingPolicy{TrailingSlash: TrailingSlashIgnore}),
+		WithMethodOverride(),
+	)
+	app, err := module.Compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer module.Close()
+	handler, err := app.Handler()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		method   string
+		path     string
+		override string
+		status   int
+		body     string
+	}{
+		{http.MethodGet, "/users", "", http.StatusOK, "users"},
+		{http.MethodGet, "/users/", "", http.StatusOK, "users"},
+		{http.MethodGet, "/teams/", "", http.StatusOK, "teams/"},
+		{http.MethodGet, "/teams", "", http.StatusOK, "teams/"},
+		{http.MethodGet, "/both", "", http.StatusOK, "both"},
+		{http.MethodGet, "/both/", "", http.StatusOK, "both/"},
+		// The recorder keeps the body net/http would drop from HEAD responses
+		{http.MethodHead, "/teams", "", http.StatusOK, "teams/"},
+		{http.MethodPost, "/users/7/", http.MethodPut, http.StatusOK, "put user"},
+		{http.MethodGet, "/static/app.js", "", http.StatusOK, "static"},
+		{http.MethodGet, "/missing/", "", http.StatusNotFound, "404 page not found"},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		if tt.override != "" {
+			req.Header.Set("X-HTTP-Method-Override", tt.override)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != tt.status || rec.Body.String() != tt.body {
+			t.Errorf("%s %s: got %d %q, want %d %q", tt.method, tt.path, rec.Code, rec.Body.String(), tt.status, tt.body)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NamedModule can be selected by name through ApplicationOptions.EnabledModules
+type NamedModule interface {
+	Module
+	Name() string
+}
+
+// DependentModule names the modules it cannot run without
+type DependentModule interface {
+	NamedModule
+	Dependencies() []string
+}
+
+// MissingModule is a dependency that is not enabled, with the chain of
+// modules that requires it
+type MissingModule struct {
+	Name string
+	Path []string
+}
+
+type ModuleDependencyError struct {
+	Missing []MissingModule
+}
+
+func (e *ModuleDependencyError) Error() string {
+	parts := make([]string, 0, len(e.Missing))
+	for _, missing := range e.Missing {
+		parts = append(parts, fmt.Sprintf("%s (required by %s)", missing.Name, strings.Join(missing.Path, " -> ")))
+	}
+	return "missing modules: " + strings.Join(parts, ", ")
+}
+
+// selectModules keeps the modules named in enabled, plus any module without
+// a name, and checks that every dependency of the selection is enabled.
+// A nil enabled list keeps every module.
+func selectModules(modules []Module, enabled []string) ([]Module, error) {
+	named := make(map[string]NamedModule)
+	for _, module := range modules {
+		if namedModule, ok := module.(NamedModule); ok {
+			named[namedModule.Name()] = namedModule
+		}
+	}
+
+	isEnabled := func(name string) bool {
+		if enabled == nil {
+			_, exists := named[name]
+			return exists
+		}
+		for _, candidate := range enabled {
+			if candidate == name {
+				_, exists := named[name]
+				return exists
+			}
+		}
+		return false
+	}
+
+	selected := make([]Module, 0, len(modules))
+	for _, module := range modules {
+		if namedModule, ok := module.(NamedModule); ok && !isEnabled(namedModule.Name()) {
+			continue
+		}
+		selected = append(selected, module)
+	}
+
+	missing := make([]MissingModule, 0)
+	reported := make(map[string]bool)
+
+	var walk func(module NamedModule, path []string)
+	walk = func(module NamedModule, path []string) {
+		dependent, ok := module.(DependentModule)
+		if !ok {
+			return
+		}
+		path = append(path, module.Name())
+		for _, dependency := range dependent.Dependencies() {
+			if isEnabled(dependency) {
+				continue
+			}
+			if !reported[dependency] {
+				reported[dependency] = true
+				missing = append(missing, MissingModule{
+					Name: dependency,
+					Path: append([]string(nil), path...),
+				})
+			}
+			// Report what the disabled dependency would need as well
+			if next, exists := named[dependency]; exists && !contains(path, dependency) {
+				walk(next, path)
+			}
+		}
+	}
+	for _, module := range selected {
+		if namedModule, ok := module.(NamedModule); ok {
+			walk(namedModule, nil)
+		}
+	}
+
+	if len(missing) > 0 {
+		return nil, &ModuleDependencyError{Missing: missing}
+	}
+	return selected, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,199 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+const defaultShutdownHookTimeout = 10 * time.Second
+
+// ShutdownKind is what triggered a shutdown
+type ShutdownKind int
+
+const (
+	ShutdownContext ShutdownKind = iota // The context passed to Run ended
+	ShutdownSignal                      // An OS signal arrived
+	ShutdownStop                        // Application.Stop was called
+	ShutdownError                       // The server failed
+)
+
+// ShutdownReason tells hooks why the application is stopping, so they can
+// drain gracefully or abort fast
+type ShutdownReason struct {
+	Kind   ShutdownKind
+	Signal os.Signal // Set for ShutdownSignal
+	Err    error     // Set for ShutdownContext and ShutdownError
+}
+
+func (r ShutdownReason) String() string {
+	switch r.Kind {
+	case ShutdownSignal:
+		return "signal " + r.Signal.String()
+	case ShutdownStop:
+		return "stop"
+	case ShutdownError:
+		return "error: " + r.Err.Error()
+	}
+	if r.Err != nil {
+		return "context: " + r.Err.Error()
+	}
+	return "context"
+}
+
+// ShutdownModule is told why the application stops before it is destroyed
+type ShutdownModule interface {
+	Module
+	OnApplicationShutdown(reason ShutdownReason) error
+}
+
+// ShutdownHook releases a resource when the application stops
+type ShutdownHook func(ctx context.Context, reason ShutdownReason) error
+
+type ShutdownHookOptions struct {
+	Priority int           // Higher priorities run first
+	Timeout  time.Duration // Longest the hook may run before it is abandoned
+	Group    string        // Hooks of one group and priority run in parallel
+}
+
+func WithHookPriority(priority int) func(*ShutdownHookOptions) {
+	return func(o *ShutdownHookOptions) {
+		o.Priority = priority
+	}
+}
+
+func WithHookTimeout(timeout time.Duration) func(*ShutdownHookOptions) {
+	return func(o *ShutdownHookOptions) {
+		o.Timeout = timeout
+	}
+}
+
+// WithHookGroup runs the hook alongside the other hooks of group that share
+// its priority, e.g. closing several independent connection pools
+func WithHookGroup(group string) func(*ShutdownHookOptions) {
+	return func(o *ShutdownHookOptions) {
+		o.Group = group
+	}
+}
+
+type shutdownHook struct {
+	name    string
+	hook    ShutdownHook
+	options ShutdownHookOptions
+}
+
+// RegisterShutdownHook adds a hook run when the application stops, before
+// modules are destroyed. Hooks run by descending priority, then in
+// registration order; a failing or slow hook does not block the others.
+func (app *Application) RegisterShutdownHook(name string, hook ShutdownHook, opts ...func(*ShutdownHookOptions)) {
+	options := ShutdownHookOptions{Timeout: defaultShutdownHookTimeout}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	app.mu.Lock()
+	defer app.mu.Unlock()
+	app.shutdownHooks = append(app.shutdownHooks, shutdownHook{name: name, hook: hook, options: options})
+}
+
+// Stop makes Run shut the application down, as a signal would. Embedded
+// applications started with Handler stop with Shutdown.
+func (app *Application) Stop() error {
+	if state := app.lifecycle.current(); state != StateStarting && state != StateRunning {
+		return &StateTransitionError{From: state, To: StateStopping}
+	}
+
+	select {
+	case app.stop <- ShutdownReason{Kind: ShutdownStop}:
+	default:
+		// A stop is already pending
+	}
+	return nil
+}
+
+// runShutdownHooks runs every hook and joins their errors
+func (app *Application) runShutdownHooks(ctx context.Context, reason ShutdownReason) error {
+	app.mu.RLock()
+	hooks := append([]shutdownHook(nil), app.shutdownHooks...)
+	app.mu.RUnlock()
+
+	sort.SliceStable(hooks, func(i, j int) bool {
+		return hooks[i].options.Priority > hooks[j].options.Priority
+	})
+
+	errs := make([]error, 0)
+	for len(hooks) > 0 {
+		// Take the next hook with the rest of its group at this priority
+		batch := []shutdownHook{hooks[0]}
+		rest := hooks[:0:0]
+		for _, hook := range hooks[1:] {
+			if group := batch[0].options.Group; group != "" && hook.options.Group == group &&
+				hook.options.Priority == batch[0].options.Priority {
+				batch = append(batch, hook)
+				continue
+			}
+			rest = append(rest, hook)
+		}
+		hooks = rest
+
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		for _, hook := range batch {
+			wg.Add(1)
+			go func(hook shutdownHook) {
+				defer wg.Done()
+				if err := app.runShutdownHook(ctx, hook, reason); err != nil {
+					mu.Lock()
+					errs = append(errs, err)
+					mu.Unlock()
+				}
+			}(hook)
+		}
+		wg.Wait()
+	}
+	return errors.Join(errs...)
+}
+
+func (app *Application) runShutdownHook(ctx context.Context, hook shutdownHook, reason ShutdownReason) error {
+	if hook.options.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, hook.options.Timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("panic: %v", r)
+			}
+		}()
+		done <- hook.hook(ctx, reason)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	duration := time.Since(start)
+	event := StartupEvent{Event: EventShutdownHook, Module: hook.name, Duration: milliseconds(duration)}
+	if err != nil {
+		if !app.quiet() {
+			app.logger.Errorf("Shutdown hook %s failed after %v: %v", hook.name, duration, err)
+		}
+		err = fmt.Errorf("shutdown hook %s: %w", hook.name, err)
+		event.Error = err.Error()
+	} else if !app.quiet() {
+		app.logger.Infof("Shutdown hook %s finished in %v", hook.name, duration)
+	}
+	app.events.emit(event)
+	return err
+}
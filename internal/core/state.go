@@ -0,0 +1,177 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+)
+
+// AppState is a step of the application lifecycle
+type AppState int
+
+const (
+	StateCreated AppState = iota
+	StateConfigured
+	StateStarting
+	StateRunning
+	StateStopping
+	StateStopped
+	StateFailed
+)
+
+func (s AppState) String() string {
+	switch s {
+	case StateCreated:
+		return "created"
+	case StateConfigured:
+		return "configured"
+	case StateStarting:
+		return "starting"
+	case StateRunning:
+		return "running"
+	case StateStopping:
+		return "stopping"
+	case StateStopped:
+		return "stopped"
+	case StateFailed:
+		return "failed"
+	}
+	return fmt.Sprintf("AppState(%d)", int(s))
+}
+
+// terminal reports whether no transition leaves s
+func (s AppState) terminal() bool {
+	return s == StateStopped || s == StateFailed
+}
+
+var stateTransitions = map[AppState][]AppState{
+	StateCreated:    {StateConfigured, StateFailed},
+	StateConfigured: {StateStarting},
+	StateStarting:   {StateRunning, StateFailed},
+	StateRunning:    {StateStopping, StateFailed},
+	StateStopping:   {StateStopped, StateFailed},
+}
+
+var (
+	ErrInvalidTransition = errors.New("invalid lifecycle transition")
+	ErrStateUnreachable  = errors.New("lifecycle state unreachable")
+)
+
+type StateTransitionError struct {
+	From AppState
+	To   AppState
+}
+
+func (e *StateTransitionError) Error() string {
+	return fmt.Sprintf("cannot move application from %s to %s", e.From, e.To)
+}
+
+func (e *StateTransitionError) Unwrap() error {
+	return ErrInvalidTransition
+}
+
+// lifecycle tracks the application state and wakes waiters on change
+type lifecycle struct {
+	mu      sync.Mutex
+	state   AppState
+	changed chan struct{}
+}
+
+func newLifecycle() *lifecycle {
+	return &lifecycle{state: StateCreated, changed: make(chan struct{})}
+}
+
+func (l *lifecycle) current() AppState {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.state
+}
+
+// transition moves to state, or returns a StateTransitionError when state
+// cannot follow the current one
+func (l *lifecycle) transition(to AppState) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, allowed := range stateTransitions[l.state] {
+		if allowed == to {
+			l.state = to
+			close(l.changed)
+			l.changed = make(chan struct{})
+			return nil
+		}
+	}
+	return &StateTransitionError{From: l.state, To: to}
+}
+
+// settled blocks until the application has stopped or failed
+func (l *lifecycle) settled() AppState {
+	for {
+		l.mu.Lock()
+		state, changed := l.state, l.changed
+		l.mu.Unlock()
+
+		if state.terminal() {
+			return state
+		}
+		<-changed
+	}
+}
+
+// State returns the current lifecycle state
+func (app *Application) State() AppState {
+	return app.lifecycle.current()
+}
+
+// WaitForState blocks until the application reaches state. It returns
+// ErrStateUnreachable once the application has moved past state or
+// stopped without reaching it, and ctx's error if ctx ends first.
+func (app *Application) WaitForState(ctx context.Context, state AppState) error {
+	for {
+		app.lifecycle.mu.Lock()
+		current, changed := app.lifecycle.state, app.lifecycle.changed
+		app.lifecycle.mu.Unlock()
+
+		if current == state {
+			return nil
+		}
+		if current.terminal() || (current > state && state != StateFailed) {
+			return fmt.Errorf("%w: waiting for %s, application is %s", ErrStateUnreachable, state, current)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+// StartupError identifies the module and phase that stopped the application
+// from starting
+type StartupError struct {
+	Module string
+	Phase  string // "configure" or "init"
+	Err    error
+	Stack  []byte // Set when the module panicked
+}
+
+func (e *StartupError) Error() string {
+	return fmt.Sprintf("module %s failed during %s: %v", e.Module, e.Phase, e.Err)
+}
+
+func (e *StartupError) Unwrap() error {
+	return e.Err
+}
+
+// callSafely runs fn, turning a panic into an error and its stack
+func callSafely(fn func() error) (stack []byte, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			stack, err = debug.Stack(), fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return nil, fn()
+}
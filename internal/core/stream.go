@@ -0,0 +1,171 @@
+package core
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	streamBufferSize    = 32 << 10
+	streamFlushInterval = 100 * time.Millisecond
+)
+
+// ItemWriter writes one item of a streamed response. Write returns the
+// request context's error once the client has gone, so producers stop
+// fetching rows nobody will read.
+type ItemWriter interface {
+	Write(item interface{}) error
+}
+
+// StreamNDJSON streams items as newline-delimited JSON without holding the
+// result set in memory. Output is flushed when the buffer fills and at least
+// every 100ms while items are pending, so slow producers still reach the
+// client promptly.
+//
+// If produce fails before writing any item, nothing has been sent and the
+// caller can still answer with an error status. Once items have been sent
+// the status is gone: the error is logged, the items written so far are
+// flushed and the stream simply ends.
+func StreamNDJSON(c *gin.Context, produce func(w ItemWriter) error) error {
+	return newStreamWriter(c, "application/x-ndjson", "", "\n", "").run(produce)
+}
+
+// StreamJSONArray streams items as a single JSON array, for clients that
+// cannot read NDJSON. See StreamNDJSON.
+//
+// A failure after the first item leaves the array unclosed on purpose: the
+// body is then invalid JSON, so clients cannot mistake a truncated result
+// for a complete one.
+func StreamJSONArray(c *gin.Context, produce func(w ItemWriter) error) error {
+	return newStreamWriter(c, "application/json; charset=utf-8", "[", ",", "]").run(produce)
+}
+
+type streamWriter struct {
+	c         *gin.Context
+	buf       *bufio.Writer
+	encoder   *json.Encoder
+	mediaType string
+	open      string
+	separator string
+	closing   string
+	items     int
+
+	// mu guards the buffer against the periodic flush
+	mu sync.Mutex
+}
+
+func newStreamWriter(c *gin.Context, mediaType, open, separator, closing string) *streamWriter {
+	buf := bufio.NewWriterSize(c.Writer, streamBufferSize)
+	return &streamWriter{
+		c:         c,
+		buf:       buf,
+		encoder:   json.NewEncoder(buf),
+		mediaType: mediaType,
+		open:      open,
+		separator: separator,
+		closing:   closing,
+	}
+}
+
+func (w *streamWriter) run(produce func(w ItemWriter) error) error {
+	stop := w.flushEvery(streamFlushInterval)
+	err := produce(w)
+	stop()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err != nil {
+		if w.items > 0 {
+			RequestLogger(w.c).Errorf("Stream %s %s failed after %d items: %v", w.c.Request.Method, w.c.Request.URL.Path, w.items, err)
+		}
+		w.flush()
+		return err
+	}
+	return w.close()
+}
+
+// flushEvery flushes pending items on a ticker until the returned stop is
+// called, so a producer pausing between items does not hold them back
+func (w *streamWriter) flushEvery(interval time.Duration) (stop func()) {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		for {
+			select {
+			case <-ticker.C:
+				w.mu.Lock()
+				if w.buf.Buffered() > 0 {
+					w.flush()
+				}
+				w.mu.Unlock()
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		ticker.Stop()
+		close(done)
+		<-stopped
+	}
+}
+
+func (w *streamWriter) Write(item interface{}) error {
+	if err := w.c.Request.Context().Err(); err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.items == 0 {
+		w.start()
+	} else if w.separator != "\n" {
+		// The encoder already ends every item with a newline
+		w.buf.WriteString(w.separator)
+	}
+	if err := w.encoder.Encode(item); err != nil {
+		return err
+	}
+	w.items++
+	return nil
+}
+
+// start sends the headers with the first item, so a producer failing early
+// leaves the response untouched
+func (w *streamWriter) start() {
+	w.c.Header("Content-Type", w.mediaType)
+	w.c.Header("X-Content-Type-Options", "nosniff")
+	w.c.Status(http.StatusOK)
+	w.buf.WriteString(w.open)
+}
+
+func (w *streamWriter) flush() error {
+	if w.items == 0 {
+		return nil
+	}
+	if err := w.buf.Flush(); err != nil {
+		return err
+	}
+	w.c.Writer.Flush()
+	return nil
+}
+
+func (w *streamWriter) close() error {
+	if w.items == 0 {
+		w.start()
+	}
+	w.buf.WriteString(w.closing)
+	if err := w.buf.Flush(); err != nil {
+		return err
+	}
+	w.c.Writer.Flush()
+	return nil
+}
@@ -0,0 +1,147 @@
+package core
+
+import (
+	"errors"
+	"reflect"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/fx"
+)
+
+var ErrNotCompiled = errors.New("testing module is not compiled")
+
+// TestingModule assembles modules into an application for unit tests of
+// controllers and services, with chosen providers replaced by fakes:
+//
+//	module := core.NewTestingModule(user.NewUserModule())
+//	core.OverrideProvider[core.Clock](module).UseValue(core.NewFakeClock(start))
+//	app, err := module.Compile()
+//	defer module.Close()
+//	handler, _ := app.Handler()
+//
+// Compile starts the application without listening, in the "test" profile,
+// gin's test mode and with fx logging silenced.
+type TestingModule struct {
+	modules   []Module
+	options   []func(*ApplicationOptions)
+	overrides map[reflect.Type]interface{}
+	targets   []interface{}
+	app       *Application
+}
+
+func NewTestingModule(modules ...Module) *TestingModule {
+	return &TestingModule{
+		modules:   modules,
+		overrides: make(map[reflect.Type]interface{}),
+	}
+}
+
+// WithOptions configures the application, after the testing defaults
+func (m *TestingModule) WithOptions(opts ...func(*ApplicationOptions)) *TestingModule {
+	m.options = append(m.options, opts...)
+	return m
+}
+
+// ProviderOverride replaces the provider of T, see OverrideProvider
+type ProviderOverride[T any] struct {
+	module *TestingModule
+}
+
+// OverrideProvider replaces T in the container and in fx, whichever
+// provides it
+func OverrideProvider[T any](m *TestingModule) *ProviderOverride[T] {
+	return &ProviderOverride[T]{module: m}
+}
+
+// UseValue makes T resolve to value, also for modules that bind their own
+func (o *ProviderOverride[T]) UseValue(value T) *TestingModule {
+	o.module.overrides[typeOf[T]()] = value
+	return o.module
+}
+
+// Populate fills targets, pointers to fx-provided types, when the module
+// is compiled, like fx.Populate. Use Get for container bindings.
+func (m *TestingModule) Populate(targets ...interface{}) *TestingModule {
+	m.targets = append(m.targets, targets...)
+	return m
+}
+
+// Compile configures the modules with the overrides in place, then builds
+// the request pipeline. Compiling again returns the same application.
+func (m *TestingModule) Compile() (*Application, error) {
+	if m.app != nil {
+		return m.app, nil
+	}
+
+	opts := append([]func(*ApplicationOptions){
+		WithGinMode(gin.TestMode),
+		WithProfile("test"),
+		WithQuiet(),
+	}, m.options...)
+	app := NewGoblinApplication(opts...)
+	for interfaceType, implementation := range m.overrides {
+		app.container.override(interfaceType, implementation)
+	}
+	for _, module := range m.modules {
+		app.AddModule(module)
+	}
+	app.AddModule(&testingOverrides{module: m})
+
+	if err := app.Configure(); err != nil {
+		return nil, err
+	}
+	if _, err := app.Handler(); err != nil {
+		return nil, err
+	}
+	m.app = app
+	return app, nil
+}
+
+// Close shuts the compiled application down
+func (m *TestingModule) Close() error {
+	if m.app == nil {
+		return ErrNotCompiled
+	}
+	return m.app.Shutdown()
+}
+
+// Get returns the implementation of T bound in the container of the
+// compiled application
+func Get[T any](m *TestingModule) (T, error) {
+	if m.app == nil {
+		var zero T
+		return zero, ErrNotCompiled
+	}
+	return Resolve[T](m.app.container)
+}
+
+// testingOverrides carries the overrides and Populate targets into fx. It
+// is added last and has no name, so module selection always keeps it.
+type testingOverrides struct {
+	BaseModule
+	module *TestingModule
+}
+
+func (o *testingOverrides) ProvideDependencies() fx.Option {
+	options := make([]fx.Option, 0, len(o.module.overrides)+1)
+	for interfaceType, implementation := range o.module.overrides {
+		options = append(options, fx.Decorate(decorator(interfaceType, implementation)))
+	}
+	if len(o.module.targets) > 0 {
+		options = append(options, fx.Populate(o.module.targets...))
+	}
+	return fx.Options(options...)
+}
+
+// decorator builds a func() T returning implementation. fx leaves types
+// nothing provides alone.
+func decorator(interfaceType reflect.Type, implementation interface{}) interface{} {
+	value := reflect.Zero(interfaceType)
+	if implementation != nil {
+		value = reflect.ValueOf(implementation)
+	}
+	fnType := reflect.FuncOf(nil, []reflect.Type{interfaceType}, false)
+	return reflect.MakeFunc(fnType, func([]reflect.Value) []reflect.Value {
+		return []reflect.Value{value}
+	}).Interface()
+}
@@ -0,0 +1,104 @@
+package core
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/fx"
+)
+
+type mailer interface {
+	Send(to string) error
+}
+
+type smtpMailer struct{}
+
+func (smtpMailer) Send(string) error { return errors.New("no SMTP server in tests") }
+
+type recordingMailer struct {
+	sent []string
+}
+
+func (m *recordingMailer) Send(to string) error {
+	m.sent = append(m.sent, to)
+	return nil
+}
+
+// signupsModule binds its own clock in the container and provides its
+// mailer through fx, so both override paths are exercised
+type signupsModule struct {
+	BaseModule
+	clock  Clock
+	mailer mailer
+}
+
+func (m *signupsModule) Configure(container *Container) {
+	Bind[Clock](container, NewSystemClock(nil))
+	m.clock = MustResolve[Clock](container)
+}
+
+func (m *signupsModule) ProvideDependencies() fx.Option {
+	return fx.Options(
+		fx.Provide(func() mailer { return smtpMailer{} }),
+		fx.Invoke(func(mailer mailer) { m.mailer = mailer }),
+	)
+}
+
+func (m *signupsModule) RegisterRoutes(router *gin.RouterGroup) {
+	router.POST("/signups", func(c *gin.Context) {
+		if err := m.mailer.Send("ada@example.com"); err != nil {
+			c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+			return
+		}
+		c.String(http.StatusCreated, m.clock.Now().Format(time.DateOnly))
+	})
+}
+
+func TestOverrideProvider(t *testing.T) {
+	start := time.Date(2024, 2, 29, 10, 0, 0, 0, time.UTC)
+	mail := &recordingMailer{}
+	var populated mailer
+
+	module := NewTestingModule(&signupsModule{})
+	OverrideProvider[Clock](module).UseValue(NewFakeClock(start))
+	OverrideProvider[mailer](module).UseValue(mail).Populate(&populated)
+	app, err := module.Compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer module.Close()
+
+	handler, err := app.Handler()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/signups", nil))
+	if rec.Code != http.StatusCreated || rec.Body.String() != "2024-02-29" {
+		t.Errorf("got %d %q, want 201 from the fake clock's date", rec.Code, rec.Body.String())
+	}
+	if len(mail.sent) != 1 {
+		t.Errorf("recording mailer sent %v, want one mail", mail.sent)
+	}
+
+	if clock, err := Get[Clock](module); err != nil || !clock.Now().Equal(start) {
+		t.Errorf("Get[Clock] = %v, %v; want the fake clock", clock, err)
+	}
+	if populated != mail {
+		t.Errorf("Populate got %v, want the overriding mailer", populated)
+	}
+}
+
+func TestGetBeforeCompile(t *testing.T) {
+	module := NewTestingModule()
+	if _, err := Get[Clock](module); !errors.Is(err, ErrNotCompiled) {
+		t.Errorf("Get before Compile error = %v, want ErrNotCompiled", err)
+	}
+	if err := module.Close(); !errors.Is(err, ErrNotCompiled) {
+		t.Errorf("Close before Compile error = %v, want ErrNotCompiled", err)
+	}
+}
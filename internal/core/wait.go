@@ -0,0 +1,131 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+var ErrDependencyUnavailable = errors.New("dependency unavailable")
+
+const (
+	initialWaitBackoff = 100 * time.Millisecond
+	maxWaitBackoff     = 5 * time.Second
+)
+
+// Dependency is an external service startup can wait for
+type Dependency struct {
+	Name    string
+	Check   func(ctx context.Context) error
+	Timeout time.Duration // Deadline for this dependency, 0 uses WaitFor's
+}
+
+// TCPDependency is ready once addr accepts TCP connections
+func TCPDependency(addr string) Dependency {
+	return Dependency{
+		Name: "tcp://" + addr,
+		Check: func(ctx context.Context) error {
+			var dialer net.Dialer
+			conn, err := dialer.DialContext(ctx, "tcp", addr)
+			if err != nil {
+				return err
+			}
+			return conn.Close()
+		},
+	}
+}
+
+// HTTPDependency is ready once url answers GET with 200 OK
+func HTTPDependency(url string) Dependency {
+	return Dependency{
+		Name: url,
+		Check: func(ctx context.Context) error {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+			if err != nil {
+				return err
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				return err
+			}
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				return fmt.Errorf("unexpected status %d", resp.StatusCode)
+			}
+			return nil
+		},
+	}
+}
+
+// PingDependency is ready once ping succeeds, e.g. (*sql.DB).PingContext
+func PingDependency(name string, ping func(ctx context.Context) error) Dependency {
+	return Dependency{Name: name, Check: ping}
+}
+
+// WaitFor blocks until every dependency is reachable, retrying each with
+// exponential backoff until its deadline. Use it from a module's OnInit to
+// make startup ordering in containers robust. Each failed attempt is
+// logged; Application.WaitFor follows the application's Quiet option.
+func WaitFor(ctx context.Context, deadline time.Duration, deps ...Dependency) error {
+	return waitFor(ctx, log.Printf, deadline, deps...)
+}
+
+// WaitFor is WaitFor logging failed attempts through the application's
+// logger, or not at all in quiet mode
+func (app *Application) WaitFor(ctx context.Context, deadline time.Duration, deps ...Dependency) error {
+	logf := app.logger.Infof
+	if app.quiet() {
+		logf = func(string, ...interface{}) {}
+	}
+	return waitFor(ctx, logf, deadline, deps...)
+}
+
+func waitFor(ctx context.Context, logf func(format string, args ...interface{}), deadline time.Duration, deps ...Dependency) error {
+	var wg sync.WaitGroup
+	errs := make([]error, len(deps))
+
+	for i, dep := range deps {
+		wg.Add(1)
+		go func(i int, dep Dependency) {
+			defer wg.Done()
+
+			timeout := deadline
+			if dep.Timeout > 0 {
+				timeout = dep.Timeout
+			}
+			depCtx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			errs[i] = waitForDependency(depCtx, dep, logf)
+		}(i, dep)
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+func waitForDependency(ctx context.Context, dep Dependency, logf func(format string, args ...interface{})) error {
+	backoff := initialWaitBackoff
+	for {
+		err := dep.Check(ctx)
+		if err == nil {
+			return nil
+		}
+		logf("Waiting for %s: %v", dep.Name, err)
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w: %s: %v", ErrDependencyUnavailable, dep.Name, err)
+		case <-time.After(backoff):
+		}
+
+		if backoff *= 2; backoff > maxWaitBackoff {
+			backoff = maxWaitBackoff
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWaitForLogsUnlessQuiet(t *testing.T) {
+	var out bytes.Buffer
+	defer log.SetOutput(log.Writer())
+	log.SetOutput(&out)
+
+	attempts := 0
+	flaky := PingDependency("flaky", func(context.Context) error {
+		if attempts++; attempts%2 == 1 {
+			return errors.New("not yet")
+		}
+		return nil
+	})
+
+	tests := []struct {
+		name    string
+		options []func(*ApplicationOptions)
+		logged  bool
+	}{
+		{"default", nil, true},
+		{"quiet", []func(*ApplicationOptions){WithQuiet()}, false},
+		{"startup events", []func(*ApplicationOptions){WithStartupEvents(new(bytes.Buffer))}, false},
+	}
+	for _, tt := range tests {
+		out.Reset()
+		app := NewGoblinApplication(tt.options...)
+		if err := app.WaitFor(context.Background(), time.Second, flaky); err != nil {
+			t.Fatalf("%s: WaitFor() = %v", tt.name, err)
+		}
+		if logged := strings.Contains(out.String(), "Waiting for flaky: not yet"); logged != tt.logged {
+			t.Errorf("%s: logged = %v, want %v (output %q)", tt.name, logged, tt.logged, out.String())
+		}
+	}
+}
@@ -0,0 +1,32 @@
+// Package goblintest helps test applications built on goblin
+package goblintest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/calummacc/goblin/internal/core"
+)
+
+// ExpectPipeline fails t unless route, e.g. "GET /users/:id", runs exactly
+// the expected handlers in order, so changes to middleware, guards or
+// module wiring show up in review. Names are as reported by
+// Application.Pipeline, e.g. "middleware.Logger" or
+// "user.Controller.GetUser". The application is started with Handler if
+// needed; shut it down with Shutdown when done.
+func ExpectPipeline(t testing.TB, app *core.Application, route string, expected ...string) {
+	t.Helper()
+
+	if _, err := app.Handler(); err != nil {
+		t.Fatalf("starting application: %v", err)
+	}
+	pipeline, err := app.Pipeline(route)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if strings.Join(pipeline.Handlers, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("pipeline of %s changed\nwant:\n\t%s\ngot:\n\t%s",
+			route, strings.Join(expected, "\n\t"), strings.Join(pipeline.Handlers, "\n\t"))
+	}
+}
@@ -0,0 +1,191 @@
+package health
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/calummacc/goblin/internal/core"
+	"github.com/gin-gonic/gin"
+)
+
+type Status string
+
+const (
+	StatusUp   Status = "up"
+	StatusDown Status = "down"
+)
+
+type Options struct {
+	Prefix     string            // Prefix the endpoints are mounted under
+	Timeout    time.Duration     // Deadline for all readiness checks together
+	Indicators []HealthIndicator // Checked along with the contributed ones
+}
+
+var defaultOptions = Options{
+	Prefix:  "/health",
+	Timeout: 5 * time.Second,
+}
+
+func WithPrefix(prefix string) func(*Options) {
+	return func(opts *Options) {
+		opts.Prefix = prefix
+	}
+}
+
+func WithTimeout(timeout time.Duration) func(*Options) {
+	return func(opts *Options) {
+		opts.Timeout = timeout
+	}
+}
+
+func WithIndicator(indicator HealthIndicator) func(*Options) {
+	return func(opts *Options) {
+		opts.Indicators = append(opts.Indicators, indicator)
+	}
+}
+
+// Check is the result of one indicator
+type Check struct {
+	Status Status `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Report is the body of the health endpoints
+type Report struct {
+	Status Status           `json:"status"`
+	State  string           `json:"state"`
+	Checks map[string]Check `json:"checks,omitempty"`
+}
+
+// HealthModule serves <prefix>/live, which fails only once the application
+// has failed, and <prefix>/ready, which succeeds only while the
+// application is running and every indicator is healthy. Readiness drops
+// as soon as shutdown starts, so load balancers stop routing traffic
+// while in-flight requests drain.
+type HealthModule struct {
+	core.BaseModule
+	app        *core.Application
+	options    Options
+	container  *core.Container
+	indicators []HealthIndicator
+}
+
+func NewHealthModule(app *core.Application, opts ...func(*Options)) *HealthModule {
+	options := defaultOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return &HealthModule{app: app, options: options}
+}
+
+func (m *HealthModule) Configure(container *core.Container) {
+	m.container = container
+}
+
+// OnInit collects the indicators contributed by other modules
+func (m *HealthModule) OnInit() error {
+	contributed, err := core.Group[HealthIndicator](m.container, Group)
+	if err != nil {
+		return err
+	}
+	m.indicators = append(append([]HealthIndicator(nil), m.options.Indicators...), contributed...)
+	return nil
+}
+
+func (m *HealthModule) OnDestroy() error {
+	return nil
+}
+
+func (m *HealthModule) RegisterRoutes(router *gin.RouterGroup) {
+	health := router.Group(m.options.Prefix)
+	{
+		health.GET("/live", m.getLive)
+		health.GET("/ready", m.getReady)
+	}
+}
+
+func (m *HealthModule) getLive(ctx *gin.Context) {
+	state := m.app.State()
+	report := Report{Status: StatusUp, State: state.String()}
+	if state == core.StateFailed {
+		report.Status = StatusDown
+		ctx.JSON(http.StatusServiceUnavailable, report)
+		return
+	}
+	ctx.JSON(http.StatusOK, report)
+}
+
+func (m *HealthModule) getReady(ctx *gin.Context) {
+	state := m.app.State()
+	report := Report{Status: StatusDown, State: state.String()}
+	if state != core.StateRunning {
+		ctx.JSON(http.StatusServiceUnavailable, report)
+		return
+	}
+
+	report.Checks = m.Check(ctx.Request.Context())
+	report.Status = StatusUp
+	for _, check := range report.Checks {
+		if check.Status != StatusUp {
+			report.Status = StatusDown
+		}
+	}
+
+	if report.Status != StatusUp {
+		ctx.JSON(http.StatusServiceUnavailable, report)
+		return
+	}
+	ctx.JSON(http.StatusOK, report)
+}
+
+// Check runs every indicator concurrently and returns their results by name
+func (m *HealthModule) Check(ctx context.Context) map[string]Check {
+	ctx, cancel := context.WithTimeout(ctx, m.options.Timeout)
+	defer cancel()
+
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		checks = make(map[string]Check, len(m.indicators))
+	)
+	for _, indicator := range m.indicators {
+		wg.Add(1)
+		go func(indicator HealthIndicator) {
+			defer wg.Done()
+			check := Check{Status: StatusUp}
+			if err := checkSafely(ctx, indicator); err != nil {
+				check = Check{Status: StatusDown, Error: err.Error()}
+			}
+			mu.Lock()
+			checks[indicator.Name()] = check
+			mu.Unlock()
+		}(indicator)
+	}
+	wg.Wait()
+	return checks
+}
+
+// checkSafely runs an indicator, treating a panic or a check that outlives
+// the deadline as unhealthy
+func checkSafely(ctx context.Context, indicator HealthIndicator) error {
+	result := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("Health indicator %s panic: %v", indicator.Name(), r)
+				result <- errPanic
+			}
+		}()
+		result <- indicator.Check(ctx)
+	}()
+
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,46 @@
+package health
+
+import (
+	"context"
+	"errors"
+
+	"github.com/calummacc/goblin/internal/core"
+)
+
+// Group is the container group modules contribute indicators to:
+//
+//	core.Contribute[health.HealthIndicator](container, health.Group, indicator)
+const Group = "health"
+
+var errPanic = errors.New("indicator panicked")
+
+// HealthIndicator reports whether something the application needs, such
+// as a database or a broker, is usable. Check returns nil when healthy.
+type HealthIndicator interface {
+	Name() string
+	Check(ctx context.Context) error
+}
+
+type indicatorFunc struct {
+	name  string
+	check func(ctx context.Context) error
+}
+
+func (i indicatorFunc) Name() string {
+	return i.name
+}
+
+func (i indicatorFunc) Check(ctx context.Context) error {
+	return i.check(ctx)
+}
+
+// Indicator turns a check function into a HealthIndicator
+func Indicator(name string, check func(ctx context.Context) error) HealthIndicator {
+	return indicatorFunc{name: name, check: check}
+}
+
+// FromDependency reuses a startup dependency, e.g. core.TCPDependency, as
+// a readiness check
+func FromDependency(dependency core.Dependency) HealthIndicator {
+	return indicatorFunc{name: dependency.Name, check: dependency.Check}
+}
@@ -0,0 +1,226 @@
+package i18n
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Catalog keys holding the locale's formats. Layouts use Go reference
+// time; separators are single strings such as "," or ".".
+const (
+	FormatDateTimeKey = "format.datetime"
+	FormatDateKey     = "format.date"
+	FormatTimeKey     = "format.time"
+	DecimalSepKey     = "format.decimal"
+	GroupSepKey       = "format.group"
+)
+
+var defaultFormats = map[string]string{
+	FormatDateTimeKey: "2006-01-02 15:04",
+	FormatDateKey:     "2006-01-02",
+	FormatTimeKey:     "15:04",
+	DecimalSepKey:     ".",
+	GroupSepKey:       ",",
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func (t *Translator) format(locale, key string) string {
+	if message, found := t.lookup(locale, key); found {
+		return message["other"]
+	}
+	return defaultFormats[key]
+}
+
+// FormatTime formats value as a "datetime", "date" or "time" in the
+// locale carried by ctx
+func (t *Translator) FormatTime(ctx context.Context, kind string, value time.Time) string {
+	key := FormatDateTimeKey
+	switch kind {
+	case "date":
+		key = FormatDateKey
+	case "time":
+		key = FormatTimeKey
+	}
+	return value.Format(t.format(Locale(ctx), key))
+}
+
+// FormatNumber formats value with the locale's separators, rounding to
+// precision decimals; a negative precision keeps every significant digit
+func (t *Translator) FormatNumber(ctx context.Context, value float64, precision int) string {
+	return t.formatDigits(Locale(ctx), strconv.FormatFloat(value, 'f', precision, 64))
+}
+
+// formatDigits applies the locale's separators to a number in plain
+// decimal notation
+func (t *Translator) formatDigits(locale, text string) string {
+	sign := ""
+	if strings.HasPrefix(text, "-") {
+		sign, text = "-", text[1:]
+	}
+	whole, fraction, hasFraction := strings.Cut(text, ".")
+
+	var grouped strings.Builder
+	separator := t.format(locale, GroupSepKey)
+	for i, digit := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			grouped.WriteString(separator)
+		}
+		grouped.WriteRune(digit)
+	}
+
+	if !hasFraction {
+		return sign + grouped.String()
+	}
+	return sign + grouped.String() + t.format(locale, DecimalSepKey) + fraction
+}
+
+// Localize returns v ready to be rendered as JSON, with every struct field
+// tagged `localize:"datetime"`, "date", "time" or "number" formatted for
+// the locale carried by ctx. Structs without such fields are returned as
+// they are; structs with them become maps keyed by their JSON names.
+func (t *Translator) Localize(ctx context.Context, v interface{}) interface{} {
+	value, _ := t.localize(ctx, reflect.ValueOf(v))
+	return value
+}
+
+func (t *Translator) localize(ctx context.Context, v reflect.Value) (interface{}, bool) {
+	if !v.IsValid() {
+		return nil, false
+	}
+	original := v.Interface()
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return original, false
+		}
+		if value, changed := t.localize(ctx, v.Elem()); changed {
+			return value, true
+		}
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return original, false
+		}
+		items := make([]interface{}, v.Len())
+		changed := false
+		for i := range items {
+			var itemChanged bool
+			items[i], itemChanged = t.localize(ctx, v.Index(i))
+			changed = changed || itemChanged
+		}
+		if changed {
+			return items, true
+		}
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return original, false
+		}
+		fields := make(map[string]interface{}, v.Len())
+		changed := false
+		for iter := v.MapRange(); iter.Next(); {
+			var fieldChanged bool
+			fields[iter.Key().String()], fieldChanged = t.localize(ctx, iter.Value())
+			changed = changed || fieldChanged
+		}
+		if changed {
+			return fields, true
+		}
+	case reflect.Struct:
+		if v.Type() == timeType {
+			return original, false
+		}
+		fields := make(map[string]interface{})
+		if t.localizeStruct(ctx, v, fields) {
+			return fields, true
+		}
+	}
+	return original, false
+}
+
+// localizeStruct collects the JSON fields of v into fields, following the
+// encoding/json rules for names, omitempty, "-" and embedded structs
+func (t *Translator) localizeStruct(ctx context.Context, v reflect.Value, fields map[string]interface{}) bool {
+	if _, ok := v.Interface().(json.Marshaler); ok {
+		return false
+	}
+
+	changed := false
+	structType := v.Type()
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		value := v.Field(i)
+
+		if field.Anonymous && name == "" {
+			embedded := value
+			if embedded.Kind() == reflect.Pointer {
+				if embedded.IsNil() {
+					continue
+				}
+				embedded = embedded.Elem()
+			}
+			// Fields promoted through an unexported embedded type cannot be
+			// read by reflection and are left out
+			if embedded.Kind() == reflect.Struct && embedded.CanInterface() {
+				changed = t.localizeStruct(ctx, embedded, fields) || changed
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if strings.Contains(options, "omitempty") && value.IsZero() {
+			continue
+		}
+
+		if kind := field.Tag.Get("localize"); kind != "" {
+			if formatted, ok := t.formatField(ctx, kind, value); ok {
+				fields[name] = formatted
+				changed = true
+				continue
+			}
+		}
+		var fieldChanged bool
+		fields[name], fieldChanged = t.localize(ctx, value)
+		changed = changed || fieldChanged
+	}
+	return changed
+}
+
+func (t *Translator) formatField(ctx context.Context, kind string, value reflect.Value) (string, bool) {
+	for value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return "", false
+		}
+		value = value.Elem()
+	}
+
+	switch kind {
+	case "datetime", "date", "time":
+		if instant, ok := value.Interface().(time.Time); ok {
+			return t.FormatTime(ctx, kind, instant), true
+		}
+	case "number":
+		switch {
+		case value.CanInt():
+			return t.formatDigits(Locale(ctx), strconv.FormatInt(value.Int(), 10)), true
+		case value.CanUint():
+			return t.formatDigits(Locale(ctx), strconv.FormatUint(value.Uint(), 10)), true
+		case value.CanFloat():
+			return t.FormatNumber(ctx, value.Float(), -1), true
+		}
+	}
+	return "", false
+}
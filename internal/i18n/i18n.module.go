@@ -0,0 +1,90 @@
+package i18n
+
+import (
+	"io/fs"
+
+	"github.com/calummacc/goblin/internal/core"
+	"go.uber.org/fx"
+)
+
+type Options struct {
+	Fallback string // Locale used when a request matches no catalog
+	Catalogs []catalogSource
+}
+
+type catalogSource struct {
+	fsys fs.FS
+	dir  string
+}
+
+var defaultOptions = Options{
+	Fallback: "en",
+}
+
+func WithFallback(locale string) func(*Options) {
+	return func(opts *Options) {
+		opts.Fallback = locale
+	}
+}
+
+// WithCatalogs loads every <locale>.json file in dir of fsys, typically an
+// embed.FS
+func WithCatalogs(fsys fs.FS, dir string) func(*Options) {
+	return func(opts *Options) {
+		opts.Catalogs = append(opts.Catalogs, catalogSource{fsys: fsys, dir: dir})
+	}
+}
+
+// I18nModule makes a Translator available to controllers and filters,
+// through the container in Configure and through fx for constructors.
+// Mount its locale middleware on the engine so every route sees the
+// negotiated locale:
+//
+//	translations := i18n.ForRoot(i18n.WithCatalogs(locales, "locales"))
+//	app.GetEngine().Use(translations.Translator().Middleware())
+//	app.AddModule(translations)
+type I18nModule struct {
+	core.BaseModule
+	translator *Translator
+	err        error
+}
+
+// ForRoot loads the catalogs right away, so the translator can be used
+// while assembling the application. A loading error fails startup.
+func ForRoot(opts ...func(*Options)) *I18nModule {
+	options := defaultOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	module := &I18nModule{translator: NewTranslator(options.Fallback)}
+	for _, source := range options.Catalogs {
+		if err := module.translator.LoadFS(source.fsys, source.dir); err != nil {
+			module.err = err
+			break
+		}
+	}
+	return module
+}
+
+func (m *I18nModule) Translator() *Translator {
+	return m.translator
+}
+
+func (m *I18nModule) Configure(container *core.Container) {
+	core.Bind(container, m.translator)
+}
+
+func (m *I18nModule) ProvideDependencies() fx.Option {
+	return fx.Provide(func() (*Translator, error) {
+		return m.translator, m.err
+	})
+}
+
+func (m *I18nModule) OnInit() error {
+	return m.err
+}
+
+func (m *I18nModule) OnDestroy() error {
+	return nil
+}
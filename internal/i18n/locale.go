@@ -0,0 +1,154 @@
+package i18n
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LocaleKey is the gin context key holding the resolved locale
+const LocaleKey = "goblin.locale"
+
+type localeContextKey struct{}
+
+// WithLocale returns a context carrying locale, for use outside a request
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeContextKey{}, locale)
+}
+
+// Locale returns the locale carried by ctx, which may be a *gin.Context
+// that went through the locale middleware
+func Locale(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if locale, ok := ctx.Value(localeContextKey{}).(string); ok {
+		return locale
+	}
+	if locale, ok := ctx.Value(LocaleKey).(string); ok {
+		return locale
+	}
+	return ""
+}
+
+type LocaleOptions struct {
+	QueryParam string
+	CookieName string
+}
+
+func WithQueryParam(name string) func(*LocaleOptions) {
+	return func(o *LocaleOptions) {
+		o.QueryParam = name
+	}
+}
+
+func WithCookieName(name string) func(*LocaleOptions) {
+	return func(o *LocaleOptions) {
+		o.CookieName = name
+	}
+}
+
+// Middleware resolves the request locale from the query parameter, the
+// cookie, then Accept-Language, keeping the first one the translator has a
+// catalog for. The fallback locale is used when nothing matches.
+func (t *Translator) Middleware(opts ...func(*LocaleOptions)) gin.HandlerFunc {
+	options := LocaleOptions{
+		QueryParam: "lang",
+		CookieName: "lang",
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return func(c *gin.Context) {
+		candidates := make([]string, 0, 4)
+		if options.QueryParam != "" {
+			if value := c.Query(options.QueryParam); value != "" {
+				candidates = append(candidates, value)
+			}
+		}
+		if options.CookieName != "" {
+			if value, err := c.Cookie(options.CookieName); err == nil && value != "" {
+				candidates = append(candidates, value)
+			}
+		}
+		candidates = append(candidates, parseAcceptLanguage(c.GetHeader("Accept-Language"))...)
+
+		locale := t.Match(candidates...)
+		c.Set(LocaleKey, locale)
+		c.Request = c.Request.WithContext(WithLocale(c.Request.Context(), locale))
+		c.Header("Content-Language", locale)
+		c.Next()
+	}
+}
+
+// Match returns the first candidate with a catalog, trying each one's base
+// language as well, or the fallback locale
+func (t *Translator) Match(candidates ...string) string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	for _, candidate := range candidates {
+		candidate = normalizeLocale(candidate)
+		if _, exists := t.catalogs[candidate]; exists {
+			return candidate
+		}
+		if _, exists := t.catalogs[baseLanguage(candidate)]; exists {
+			return baseLanguage(candidate)
+		}
+	}
+	return t.fallback
+}
+
+// parseAcceptLanguage returns the languages of an Accept-Language header
+// ordered by quality
+func parseAcceptLanguage(header string) []string {
+	type weighted struct {
+		tag     string
+		quality float64
+	}
+
+	languages := make([]weighted, 0)
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		quality := 1.0
+		if value, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+		if quality <= 0 {
+			continue
+		}
+		languages = append(languages, weighted{tag: tag, quality: quality})
+	}
+
+	sort.SliceStable(languages, func(i, j int) bool {
+		return languages[i].quality > languages[j].quality
+	})
+
+	tags := make([]string, len(languages))
+	for i, language := range languages {
+		tags[i] = language.tag
+	}
+	return tags
+}
+
+// normalizeLocale turns "en_us" or "EN-us" into "en-US"
+func normalizeLocale(locale string) string {
+	locale = strings.ReplaceAll(strings.TrimSpace(locale), "_", "-")
+	base, region, found := strings.Cut(locale, "-")
+	if !found {
+		return strings.ToLower(base)
+	}
+	return strings.ToLower(base) + "-" + strings.ToUpper(region)
+}
@@ -0,0 +1,68 @@
+package i18n
+
+// PluralCategory returns the CLDR plural category of count for locale,
+// covering the common rule families. Unknown languages use the English rule.
+func PluralCategory(locale string, count int) string {
+	n := count
+	if n < 0 {
+		n = -n
+	}
+
+	switch baseLanguage(locale) {
+	case "ja", "ko", "zh", "vi", "th", "id":
+		return "other"
+
+	case "fr", "pt":
+		if n == 0 || n == 1 {
+			return "one"
+		}
+		return "other"
+
+	case "ru", "uk", "be", "sr", "hr", "bs":
+		switch {
+		case n%10 == 1 && n%100 != 11:
+			return "one"
+		case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+			return "few"
+		}
+		return "many"
+
+	case "pl":
+		switch {
+		case n == 1:
+			return "one"
+		case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+			return "few"
+		}
+		return "many"
+
+	case "cs", "sk":
+		switch {
+		case n == 1:
+			return "one"
+		case n >= 2 && n <= 4:
+			return "few"
+		}
+		return "other"
+
+	case "ar":
+		switch {
+		case n == 0:
+			return "zero"
+		case n == 1:
+			return "one"
+		case n == 2:
+			return "two"
+		case n%100 >= 3 && n%100 <= 10:
+			return "few"
+		case n%100 >= 11:
+			return "many"
+		}
+		return "other"
+	}
+
+	if n == 1 {
+		return "one"
+	}
+	return "other"
+}
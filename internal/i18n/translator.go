@@ -0,0 +1,150 @@
+package i18n
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Message is a catalog entry: either a plain string or plural forms keyed
+// by category ("zero", "one", "few", "many", "other").
+type Message map[string]string
+
+func (m *Message) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*m = Message{"other": text}
+		return nil
+	}
+
+	var forms map[string]string
+	if err := json.Unmarshal(data, &forms); err != nil {
+		return err
+	}
+	*m = forms
+	return nil
+}
+
+// Translator holds message catalogs per locale
+type Translator struct {
+	mu       sync.RWMutex
+	fallback string
+	catalogs map[string]map[string]Message
+}
+
+func NewTranslator(fallback string) *Translator {
+	return &Translator{
+		fallback: fallback,
+		catalogs: make(map[string]map[string]Message),
+	}
+}
+
+// Add merges messages into the catalog of locale
+func (t *Translator) Add(locale string, messages map[string]Message) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	catalog, exists := t.catalogs[locale]
+	if !exists {
+		catalog = make(map[string]Message, len(messages))
+		t.catalogs[locale] = catalog
+	}
+	for key, message := range messages {
+		catalog[key] = message
+	}
+}
+
+// LoadFS loads every <locale>.json file in dir, typically from an embed.FS
+func (t *Translator) LoadFS(fsys fs.FS, dir string) error {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".json" {
+			continue
+		}
+
+		data, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return err
+		}
+		messages := make(map[string]Message)
+		if err := json.Unmarshal(data, &messages); err != nil {
+			return fmt.Errorf("i18n: %s: %w", entry.Name(), err)
+		}
+		t.Add(strings.TrimSuffix(entry.Name(), ".json"), messages)
+	}
+	return nil
+}
+
+// Locales lists the locales with a catalog
+func (t *Translator) Locales() []string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	locales := make([]string, 0, len(t.catalogs))
+	for locale := range t.catalogs {
+		locales = append(locales, locale)
+	}
+	return locales
+}
+
+// T translates key for the locale carried by ctx, formatting args into the
+// message with fmt verbs. Unknown keys are returned as is.
+func (t *Translator) T(ctx context.Context, key string, args ...interface{}) string {
+	return t.translate(Locale(ctx), key, "other", args)
+}
+
+// N translates key choosing the plural form for count. A form shows the
+// count through a {count} placeholder, formatted with the locale's digit
+// grouping, so forms such as "One user" need not mention it:
+//
+//	{"users": {"one": "One user", "other": "{count} users in %s"}}
+func (t *Translator) N(ctx context.Context, key string, count int, args ...interface{}) string {
+	locale := Locale(ctx)
+	text := t.translate(locale, key, PluralCategory(locale, count), args)
+	return strings.ReplaceAll(text, countPlaceholder, t.formatDigits(locale, strconv.Itoa(count)))
+}
+
+const countPlaceholder = "{count}"
+
+func (t *Translator) translate(locale, key, category string, args []interface{}) string {
+	message, found := t.lookup(locale, key)
+	if !found {
+		return key
+	}
+
+	text, exists := message[category]
+	if !exists {
+		text = message["other"]
+	}
+	if len(args) == 0 {
+		return text
+	}
+	return fmt.Sprintf(text, args...)
+}
+
+// lookup tries the locale, its base language, then the fallback locale
+func (t *Translator) lookup(locale, key string) (Message, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	for _, candidate := range []string{locale, baseLanguage(locale), t.fallback} {
+		if message, exists := t.catalogs[candidate][key]; exists {
+			return message, true
+		}
+	}
+	return nil, false
+}
+
+func baseLanguage(locale string) string {
+	base, _, _ := strings.Cut(locale, "-")
+	return base
+}
@@ -0,0 +1,107 @@
+package i18n
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+)
+
+func newTestTranslator(t *testing.T) *Translator {
+	t.Helper()
+	translator := NewTranslator("en")
+	translator.Add("en", map[string]Message{
+		"user.created": {"other": "Created %s"},
+		"users":        {"one": "One user", "other": "{count} users"},
+		"users.in":     {"one": "One user in %s", "other": "{count} users in %s"},
+	})
+	translator.Add("ru", map[string]Message{
+		"users": {"one": "{count} пользователь", "few": "{count} пользователя", "many": "{count} пользователей"},
+	})
+	translator.Add("de", map[string]Message{
+		GroupSepKey: {"other": "."},
+		"users":     {"one": "Ein Benutzer", "other": "{count} Benutzer"},
+	})
+	return translator
+}
+
+func TestTranslatorT(t *testing.T) {
+	translator := newTestTranslator(t)
+
+	tests := []struct {
+		locale string
+		key    string
+		args   []interface{}
+		want   string
+	}{
+		{"en", "user.created", []interface{}{"alice"}, "Created alice"},
+		{"en-GB", "user.created", []interface{}{"alice"}, "Created alice"},
+		{"fr", "user.created", []interface{}{"alice"}, "Created alice"},
+		{"en", "missing.key", nil, "missing.key"},
+	}
+	for _, tt := range tests {
+		ctx := WithLocale(context.Background(), tt.locale)
+		if got := translator.T(ctx, tt.key, tt.args...); got != tt.want {
+			t.Errorf("T(%s, %q) = %q, want %q", tt.locale, tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestTranslatorN(t *testing.T) {
+	translator := newTestTranslator(t)
+
+	tests := []struct {
+		locale string
+		key    string
+		count  int
+		args   []interface{}
+		want   string
+	}{
+		// Forms without {count} must not get the count appended
+		{"en", "users", 1, nil, "One user"},
+		{"en", "users", 0, nil, "0 users"},
+		{"en", "users", 2, nil, "2 users"},
+		{"en", "users", 1234, nil, "1,234 users"},
+		{"en", "users.in", 1, []interface{}{"Berlin"}, "One user in Berlin"},
+		{"en", "users.in", 3, []interface{}{"Berlin"}, "3 users in Berlin"},
+		{"ru", "users", 1, nil, "1 пользователь"},
+		{"ru", "users", 3, nil, "3 пользователя"},
+		{"ru", "users", 5, nil, "5 пользователей"},
+		{"ru", "users", 21, nil, "21 пользователь"},
+		{"de", "users", 1, nil, "Ein Benutzer"},
+		{"de", "users", 1234, nil, "1.234 Benutzer"},
+	}
+	for _, tt := range tests {
+		ctx := WithLocale(context.Background(), tt.locale)
+		if got := translator.N(ctx, tt.key, tt.count, tt.args...); got != tt.want {
+			t.Errorf("N(%s, %q, %d) = %q, want %q", tt.locale, tt.key, tt.count, got, tt.want)
+		}
+	}
+}
+
+func TestForRootLoadsCatalogs(t *testing.T) {
+	fsys := fstest.MapFS{
+		"locales/en.json": {Data: []byte(`{"hello": "Hello", "items": {"one": "One item", "other": "{count} items"}}`)},
+		"locales/de.json": {Data: []byte(`{"hello": "Hallo"}`)},
+	}
+	module := ForRoot(WithCatalogs(fsys, "locales"))
+	if err := module.OnInit(); err != nil {
+		t.Fatalf("OnInit() = %v", err)
+	}
+
+	ctx := WithLocale(context.Background(), "de")
+	if got := module.Translator().T(ctx, "hello"); got != "Hallo" {
+		t.Errorf("T(de, hello) = %q, want Hallo", got)
+	}
+	if got := module.Translator().N(ctx, "items", 1); got != "One item" {
+		t.Errorf("N(de, items, 1) = %q, want One item", got)
+	}
+}
+
+func TestForRootReportsInvalidCatalog(t *testing.T) {
+	fsys := fstest.MapFS{
+		"locales/en.json": {Data: []byte(`{"hello": 1}`)},
+	}
+	if err := ForRoot(WithCatalogs(fsys, "locales")).OnInit(); err == nil {
+		t.Error("OnInit() = nil, want the catalog error")
+	}
+}
@@ -0,0 +1,36 @@
+package jobs
+
+import (
+	"context"
+	"time"
+)
+
+type Status string
+
+const (
+	StatusPending   Status = "pending"
+	StatusRunning   Status = "running"
+	StatusSucceeded Status = "succeeded"
+	StatusFailed    Status = "failed"
+)
+
+// Func is the work behind an accepted request. Its result is returned to
+// clients polling the job.
+type Func func(ctx context.Context) (interface{}, error)
+
+// Job is the status document served at /jobs/:id
+type Job struct {
+	ID         string      `json:"id"`
+	Status     Status      `json:"status"`
+	Result     interface{} `json:"result,omitempty"`
+	Error      string      `json:"error,omitempty"`
+	CreatedAt  time.Time   `json:"created_at"`
+	FinishedAt *time.Time  `json:"finished_at,omitempty"`
+	ExpiresAt  *time.Time  `json:"expires_at,omitempty"`
+
+	done chan struct{}
+}
+
+func (j *Job) finished() bool {
+	return j.Status == StatusSucceeded || j.Status == StatusFailed
+}
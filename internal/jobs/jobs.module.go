@@ -0,0 +1,262 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/calummacc/goblin/internal/core"
+	"github.com/gin-gonic/gin"
+)
+
+type Options struct {
+	Prefix  string        // Prefix the status route is mounted under
+	TTL     time.Duration // How long finished jobs can be fetched
+	Workers int           // Jobs run at once, the rest wait as pending
+	MaxWait time.Duration // Longest a status request may long-poll with ?wait=
+}
+
+var defaultOptions = Options{
+	Prefix:  "/jobs",
+	TTL:     time.Hour,
+	Workers: 4,
+	MaxWait: 30 * time.Second,
+}
+
+func WithPrefix(prefix string) func(*Options) {
+	return func(opts *Options) {
+		opts.Prefix = prefix
+	}
+}
+
+func WithTTL(ttl time.Duration) func(*Options) {
+	return func(opts *Options) {
+		opts.TTL = ttl
+	}
+}
+
+func WithWorkers(workers int) func(*Options) {
+	return func(opts *Options) {
+		opts.Workers = workers
+	}
+}
+
+// JobsModule runs slow work behind 202 Accepted responses and serves the
+// job status at <prefix>/:id, optionally long-polling until it finishes
+type JobsModule struct {
+	core.BaseModule
+	options Options
+	ids     core.IDGenerator
+	clock   core.Clock
+	slots   chan struct{}
+
+	mu     sync.Mutex
+	jobs   map[string]*Job
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewJobsModule(opts ...func(*Options)) *JobsModule {
+	options := defaultOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if options.Workers < 1 {
+		options.Workers = 1
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &JobsModule{
+		options: options,
+		ids:     core.NewUUIDv7Generator(nil),
+		clock:   core.NewSystemClock(nil),
+		slots:   make(chan struct{}, options.Workers),
+		jobs:    make(map[string]*Job),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+}
+
+func (m *JobsModule) Configure(container *core.Container) {
+	m.ids = core.ResolveIDGenerator(container)
+	m.clock = core.ResolveClock(container)
+}
+
+func (m *JobsModule) OnInit() error {
+	m.wg.Add(1)
+	go m.expire()
+	return nil
+}
+
+// OnDestroy cancels running jobs and waits for them to return
+func (m *JobsModule) OnDestroy() error {
+	m.cancel()
+	m.wg.Wait()
+	return nil
+}
+
+func (m *JobsModule) RegisterRoutes(router *gin.RouterGroup) {
+	router.GET(m.options.Prefix+"/:id", m.getJob)
+}
+
+// Accept starts fn in the background and answers 202 Accepted with the
+// job status URL in Location. fn gets a context detached from the request
+// that is canceled when the application stops.
+func (m *JobsModule) Accept(c *gin.Context, fn Func) Job {
+	job := &Job{
+		ID:        m.ids.NewID(),
+		Status:    StatusPending,
+		CreatedAt: m.clock.Now(),
+		done:      make(chan struct{}),
+	}
+
+	m.mu.Lock()
+	m.jobs[job.ID] = job
+	snapshot := *job
+	m.mu.Unlock()
+
+	ctx, cancel := context.WithCancel(core.Detach(c))
+	stop := context.AfterFunc(m.ctx, cancel)
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		defer stop()
+		defer cancel()
+		m.run(ctx, job, fn)
+	}()
+
+	c.Header("Location", m.options.Prefix+"/"+job.ID)
+	c.Header("Retry-After", "1")
+	c.JSON(http.StatusAccepted, snapshot)
+	return snapshot
+}
+
+func (m *JobsModule) run(ctx context.Context, job *Job, fn Func) {
+	select {
+	case m.slots <- struct{}{}:
+		defer func() { <-m.slots }()
+	case <-ctx.Done():
+		m.finish(job, nil, ctx.Err())
+		return
+	}
+	m.setStatus(job, StatusRunning)
+
+	result, err := func() (result interface{}, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("Job %s panic: %v", job.ID, r)
+				err = fmt.Errorf("panic: %v", r)
+			}
+		}()
+		return fn(ctx)
+	}()
+	m.finish(job, result, err)
+}
+
+func (m *JobsModule) setStatus(job *Job, status Status) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job.Status = status
+}
+
+func (m *JobsModule) finish(job *Job, result interface{}, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.clock.Now()
+	expires := now.Add(m.options.TTL)
+	job.FinishedAt, job.ExpiresAt = &now, &expires
+	if err != nil {
+		job.Status, job.Error = StatusFailed, err.Error()
+	} else {
+		job.Status, job.Result = StatusSucceeded, result
+	}
+	close(job.done)
+}
+
+// Get returns a copy of the job, if it exists and has not expired
+func (m *JobsModule) Get(id string) (Job, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, exists := m.jobs[id]
+	if !exists || (job.ExpiresAt != nil && !m.clock.Now().Before(*job.ExpiresAt)) {
+		return Job{}, false
+	}
+	return *job, true
+}
+
+// getJob serves the job status. With ?wait=<seconds> it holds the request
+// until the job finishes or the wait runs out.
+func (m *JobsModule) getJob(ctx *gin.Context) {
+	job, exists := m.Get(ctx.Param("id"))
+	if !exists {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "job not found"})
+		return
+	}
+
+	if raw := ctx.Query("wait"); raw != "" && !job.finished() {
+		seconds, err := strconv.Atoi(raw)
+		if err != nil || seconds < 0 {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid wait"})
+			return
+		}
+		wait := time.Duration(seconds) * time.Second
+		if wait > m.options.MaxWait {
+			wait = m.options.MaxWait
+		}
+
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		select {
+		case <-job.done:
+		case <-timer.C:
+		case <-ctx.Request.Context().Done():
+			return
+		}
+		if job, exists = m.Get(job.ID); !exists {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "job not found"})
+			return
+		}
+	}
+
+	if !job.finished() {
+		ctx.Header("Retry-After", "1")
+	}
+	ctx.JSON(http.StatusOK, job)
+}
+
+// expire drops finished jobs once their TTL has passed
+func (m *JobsModule) expire() {
+	defer m.wg.Done()
+
+	interval := m.options.TTL / 2
+	if interval < time.Second {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		now := m.clock.Now()
+		m.mu.Lock()
+		for id, job := range m.jobs {
+			if job.ExpiresAt != nil && !now.Before(*job.ExpiresAt) {
+				delete(m.jobs, id)
+			}
+		}
+		m.mu.Unlock()
+	}
+}
@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Keyer identifies the client a request belongs to. An empty key skips
+// limiting for the request.
+type Keyer func(c *gin.Context) string
+
+func KeyByIP() Keyer {
+	return func(c *gin.Context) string {
+		return c.ClientIP()
+	}
+}
+
+func KeyByHeader(name string) Keyer {
+	return func(c *gin.Context) string {
+		return c.GetHeader(name)
+	}
+}
+
+// KeyByContext keys requests by a value set earlier in the chain, e.g. the
+// authenticated user ID.
+func KeyByContext(key string) Keyer {
+	return func(c *gin.Context) string {
+		if value, exists := c.Get(key); exists {
+			return fmt.Sprint(value)
+		}
+		return ""
+	}
+}
+
+// ConcurrencyLimiter caps the number of in-flight requests per client
+type ConcurrencyLimiter struct {
+	max   int
+	keyer Keyer
+
+	mu       sync.Mutex
+	inFlight map[string]int
+	routes   map[string]int
+
+	rejected atomic.Uint64
+}
+
+func NewConcurrencyLimiter(max int, keyer Keyer) *ConcurrencyLimiter {
+	return &ConcurrencyLimiter{
+		max:      max,
+		keyer:    keyer,
+		inFlight: make(map[string]int),
+		routes:   make(map[string]int),
+	}
+}
+
+// RouteLimit gives a route template its own limit. The template is the
+// full one gin matched, global and module prefixes included, e.g.
+// "/api/v1/users/:id" for a module mounted under "/api/v1". A client's
+// requests to the route are counted apart from its others.
+func (l *ConcurrencyLimiter) RouteLimit(path string, max int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.routes[path] = max
+}
+
+// Rejected returns the number of requests rejected so far
+func (l *ConcurrencyLimiter) Rejected() uint64 {
+	return l.rejected.Load()
+}
+
+func (l *ConcurrencyLimiter) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := l.keyer(c)
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		counter, ok := l.acquire(key, c.FullPath())
+		if !ok {
+			l.rejected.Add(1)
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+				"error": "Too Many Concurrent Requests",
+			})
+			return
+		}
+		defer l.release(counter)
+
+		c.Next()
+	}
+}
+
+// acquire takes a slot for the client and returns the counter it was
+// taken from. Routes with their own limit count the client's requests to
+// them separately from the rest.
+func (l *ConcurrencyLimiter) acquire(key, route string) (string, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	max, counter := l.max, key
+	if override, exists := l.routes[route]; exists {
+		max, counter = override, key+" "+route
+	}
+	if max > 0 && l.inFlight[counter] >= max {
+		return "", false
+	}
+	l.inFlight[counter]++
+	return counter, true
+}
+
+func (l *ConcurrencyLimiter) release(counter string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.inFlight[counter]--; l.inFlight[counter] <= 0 {
+		delete(l.inFlight, counter)
+	}
+}
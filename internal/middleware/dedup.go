@@ -0,0 +1,258 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CapturedResponse is a response recorded for replay to duplicates
+type CapturedResponse struct {
+	Status int
+	Header http.Header
+	Body   []byte
+}
+
+// DedupStore keeps finished responses for the deduplication window. The
+// default store is in memory; a shared store lets every instance behind a
+// load balancer replay them.
+type DedupStore interface {
+	Get(key string) (*CapturedResponse, bool)
+	Set(key string, response *CapturedResponse, ttl time.Duration)
+}
+
+type DedupOptions struct {
+	Window  time.Duration // How long a finished response is replayed
+	Keyer   Keyer         // Principal the request belongs to, e.g. KeyByContext("userID")
+	Store   DedupStore
+	MaxBody int64 // Requests with larger bodies are not deduplicated
+}
+
+// Deduplicator collapses identical requests, by method, URL, principal and
+// body, into one execution. Duplicates arriving while it runs wait for it
+// and duplicates arriving within the window afterwards get the recorded
+// response, marked with X-Deduplicated. This absorbs retries from gateways
+// that deliver at least once. Enable it per route or group.
+type Deduplicator struct {
+	options DedupOptions
+
+	mu   sync.Mutex
+	keys map[string]*dedupEntry
+}
+
+// dedupEntry serializes the requests sharing a key. It lives in keys for
+// as long as a request holds it.
+type dedupEntry struct {
+	mu   sync.Mutex
+	refs int
+	call *dedupCall
+}
+
+type dedupCall struct {
+	done     chan struct{}
+	response *CapturedResponse
+}
+
+func NewDeduplicator(options DedupOptions) *Deduplicator {
+	if options.Window <= 0 {
+		options.Window = 10 * time.Second
+	}
+	if options.Store == nil {
+		options.Store = NewMemoryDedupStore()
+	}
+	if options.MaxBody <= 0 {
+		options.MaxBody = 1 << 20
+	}
+	return &Deduplicator{options: options, keys: make(map[string]*dedupEntry)}
+}
+
+func (d *Deduplicator) acquire(key string) *dedupEntry {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	entry, exists := d.keys[key]
+	if !exists {
+		entry = &dedupEntry{}
+		d.keys[key] = entry
+	}
+	entry.refs++
+	return entry
+}
+
+func (d *Deduplicator) release(key string, entry *dedupEntry) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if entry.refs--; entry.refs == 0 {
+		delete(d.keys, key)
+	}
+}
+
+func (d *Deduplicator) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key, ok := d.key(c)
+		if !ok {
+			c.Next()
+			return
+		}
+
+		if response, found := d.options.Store.Get(key); found {
+			replay(c, response)
+			return
+		}
+
+		// A finished execution stores its response before leaving its
+		// entry, so checking the store again under the entry's lock closes
+		// the gap between the lookup above and the lock. Only requests
+		// with this key wait on the store meanwhile.
+		entry := d.acquire(key)
+		entry.mu.Lock()
+		if call := entry.call; call != nil {
+			entry.mu.Unlock()
+			d.release(key, entry)
+			select {
+			case <-call.done:
+			case <-c.Request.Context().Done():
+				c.Abort()
+				return
+			}
+			if call.response != nil {
+				replay(c, call.response)
+				return
+			}
+			// The first execution panicked, run this one on its own
+			c.Next()
+			return
+		}
+		if response, found := d.options.Store.Get(key); found {
+			entry.mu.Unlock()
+			d.release(key, entry)
+			replay(c, response)
+			return
+		}
+		call := &dedupCall{done: make(chan struct{})}
+		entry.call = call
+		entry.mu.Unlock()
+
+		defer func() {
+			entry.mu.Lock()
+			entry.call = nil
+			entry.mu.Unlock()
+			d.release(key, entry)
+			close(call.done)
+		}()
+
+		w := &teeWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+		c.Next()
+		c.Writer = w.ResponseWriter
+
+		call.response = &CapturedResponse{
+			Status: w.Status(),
+			Header: w.Header().Clone(),
+			Body:   w.body.Bytes(),
+		}
+		// Let retries of server errors try again
+		if call.response.Status < http.StatusInternalServerError {
+			d.options.Store.Set(key, call.response, d.options.Window)
+		}
+	}
+}
+
+// key hashes the request, restoring the body for the handler. Requests
+// with bodies over MaxBody are not deduplicated.
+func (d *Deduplicator) key(c *gin.Context) (string, bool) {
+	hash := sha256.New()
+	io.WriteString(hash, c.Request.Method+"\n"+c.Request.URL.RequestURI()+"\n")
+	if d.options.Keyer != nil {
+		io.WriteString(hash, d.options.Keyer(c))
+	}
+	io.WriteString(hash, "\n")
+
+	if c.Request.Body != nil {
+		body, err := io.ReadAll(io.LimitReader(c.Request.Body, d.options.MaxBody+1))
+		c.Request.Body = readCloser{io.MultiReader(bytes.NewReader(body), c.Request.Body), c.Request.Body}
+		if err != nil || int64(len(body)) > d.options.MaxBody {
+			return "", false
+		}
+		hash.Write(body)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), true
+}
+
+func replay(c *gin.Context, response *CapturedResponse) {
+	header := c.Writer.Header()
+	for name, values := range response.Header {
+		header[name] = append([]string(nil), values...)
+	}
+	header.Set("X-Deduplicated", "true")
+	c.Writer.WriteHeader(response.Status)
+	c.Writer.Write(response.Body)
+	c.Abort()
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// teeWriter records the response while writing it through
+type teeWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *teeWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *teeWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// MemoryDedupStore keeps responses in process
+type MemoryDedupStore struct {
+	mu      sync.Mutex
+	entries map[string]memoryDedupEntry
+}
+
+type memoryDedupEntry struct {
+	response *CapturedResponse
+	expires  time.Time
+}
+
+func NewMemoryDedupStore() *MemoryDedupStore {
+	return &MemoryDedupStore{entries: make(map[string]memoryDedupEntry)}
+}
+
+func (s *MemoryDedupStore) Get(key string) (*CapturedResponse, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, exists := s.entries[key]
+	if !exists || time.Now().After(entry.expires) {
+		return nil, false
+	}
+	return entry.response, true
+}
+
+func (s *MemoryDedupStore) Set(key string, response *CapturedResponse, ttl time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for k, entry := range s.entries {
+		if now.After(entry.expires) {
+			delete(s.entries, k)
+		}
+	}
+	s.entries[key] = memoryDedupEntry{response: response, expires: now.Add(ttl)}
+}
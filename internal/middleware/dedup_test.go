@@ -0,0 +1,132 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func dedupEngine(store DedupStore, handler gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.POST("/orders", NewDeduplicator(DedupOptions{Store: store}).Middleware(), handler)
+	return engine
+}
+
+func postOrder(engine *gin.Engine, body string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(body)))
+	return rec
+}
+
+func TestDeduplicatorRunsConcurrentDuplicatesOnce(t *testing.T) {
+	var executions atomic.Int32
+	release := make(chan struct{})
+	engine := dedupEngine(nil, func(c *gin.Context) {
+		n := executions.Add(1)
+		<-release
+		c.String(http.StatusCreated, "order %d", n)
+	})
+
+	const requests = 20
+	recs := make([]*httptest.ResponseRecorder, requests)
+	var wg sync.WaitGroup
+	for i := range recs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			recs[i] = postOrder(engine, `{"item": 1}`)
+		}(i)
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if got := executions.Load(); got != 1 {
+		t.Fatalf("handler ran %d times, want 1", got)
+	}
+	replayed := 0
+	for _, rec := range recs {
+		if rec.Code != http.StatusCreated || rec.Body.String() != "order 1" {
+			t.Errorf("got %d %q, want 201 \"order 1\"", rec.Code, rec.Body.String())
+		}
+		if rec.Header().Get("X-Deduplicated") == "true" {
+			replayed++
+		}
+	}
+	if replayed != requests-1 {
+		t.Errorf("%d responses replayed, want %d", replayed, requests-1)
+	}
+
+	// Within the window, a retry gets the stored response
+	if rec := postOrder(engine, `{"item": 1}`); rec.Body.String() != "order 1" || rec.Header().Get("X-Deduplicated") != "true" {
+		t.Errorf("retry got %q, want the replayed \"order 1\"", rec.Body.String())
+	}
+}
+
+// slowStore blocks the second lookup of the first key it sees, the one a
+// deduplicator makes before running the request, until unblocked
+type slowStore struct {
+	*MemoryDedupStore
+
+	mu      sync.Mutex
+	slow    string
+	lookups int
+	blocked chan struct{}
+	unblock chan struct{}
+}
+
+func (s *slowStore) Get(key string) (*CapturedResponse, bool) {
+	s.mu.Lock()
+	if s.slow == "" {
+		s.slow = key
+	}
+	if key == s.slow {
+		s.lookups++
+	}
+	block := key == s.slow && s.lookups == 2
+	s.mu.Unlock()
+
+	if block {
+		close(s.blocked)
+		<-s.unblock
+	}
+	return s.MemoryDedupStore.Get(key)
+}
+
+func TestDeduplicatorDoesNotSerializeKeysBehindTheStore(t *testing.T) {
+	store := &slowStore{
+		MemoryDedupStore: NewMemoryDedupStore(),
+		blocked:          make(chan struct{}),
+		unblock:          make(chan struct{}),
+	}
+	engine := dedupEngine(store, func(c *gin.Context) {
+		c.String(http.StatusCreated, "ok")
+	})
+
+	slow := make(chan *httptest.ResponseRecorder)
+	go func() { slow <- postOrder(engine, `{"item": 1}`) }()
+	<-store.blocked
+
+	other := make(chan *httptest.ResponseRecorder)
+	go func() { other <- postOrder(engine, `{"item": 2}`) }()
+	select {
+	case rec := <-other:
+		if rec.Code != http.StatusCreated {
+			t.Errorf("other key got %d, want 201", rec.Code)
+		}
+	case <-time.After(2 * time.Second):
+		t.Error("a request with another key waited for the slow store lookup")
+	}
+
+	close(store.unblock)
+	if rec := <-slow; rec.Code != http.StatusCreated {
+		t.Errorf("slow key got %d, want 201", rec.Code)
+	}
+}
@@ -0,0 +1,101 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	envelopeSkipKey = "goblin.envelope.skip"
+	envelopeMetaKey = "goblin.envelope.meta"
+	envelopedKey    = "goblin.envelope.wrapped"
+)
+
+// Envelope wraps JSON responses as {"data": ..., "meta": {...}} and error
+// responses as {"errors": [...]}, so every module answers in the same
+// shape. A core.CursorPage body is unwrapped automatically: its items
+// become data and its cursors meta. Register it after Serializer so the
+// envelope keys are renamed too.
+func Envelope() gin.HandlerFunc {
+	return interceptJSON(func(c *gin.Context, status int, body interface{}) interface{} {
+		if c.GetBool(envelopeSkipKey) {
+			return body
+		}
+		if status >= http.StatusBadRequest {
+			return gin.H{"errors": envelopeErrors(body)}
+		}
+
+		meta := map[string]interface{}{}
+		if page, ok := body.(map[string]interface{}); ok && isCursorPage(page) {
+			body = page["items"]
+			for _, key := range []string{"next_cursor", "prev_cursor"} {
+				if cursor, exists := page[key]; exists {
+					meta[key] = cursor
+				}
+			}
+		}
+		if extra, exists := c.Get(envelopeMetaKey); exists {
+			for key, value := range extra.(map[string]interface{}) {
+				meta[key] = value
+			}
+		}
+
+		envelope := gin.H{"data": body}
+		if len(meta) > 0 {
+			envelope["meta"] = meta
+		}
+		// Lets an outer Fields select inside data rather than the envelope
+		c.Set(envelopedKey, true)
+		return envelope
+	})
+}
+
+// SkipEnvelope opts a route or group out of Envelope, e.g. for webhooks
+// that must answer in a third party's format
+func SkipEnvelope() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(envelopeSkipKey, true)
+		c.Next()
+	}
+}
+
+// SetMeta adds a field to the meta object of the enveloped response
+func SetMeta(c *gin.Context, key string, value interface{}) {
+	meta, _ := c.Get(envelopeMetaKey)
+	fields, ok := meta.(map[string]interface{})
+	if !ok {
+		fields = map[string]interface{}{}
+		c.Set(envelopeMetaKey, fields)
+	}
+	fields[key] = value
+}
+
+// isCursorPage reports whether body has the shape of a core.CursorPage
+func isCursorPage(body map[string]interface{}) bool {
+	if _, ok := body["items"].([]interface{}); !ok {
+		return false
+	}
+	for key := range body {
+		if key != "items" && key != "next_cursor" && key != "prev_cursor" {
+			return false
+		}
+	}
+	return true
+}
+
+// envelopeErrors turns the {"error": "..."} bodies handlers write into a
+// list of errors
+func envelopeErrors(body interface{}) []interface{} {
+	fields, ok := body.(map[string]interface{})
+	if !ok {
+		return []interface{}{body}
+	}
+	if list, ok := fields["errors"].([]interface{}); ok {
+		return list
+	}
+	if message, ok := fields["error"]; ok && len(fields) == 1 {
+		return []interface{}{gin.H{"message": message}}
+	}
+	return []interface{}{fields}
+}
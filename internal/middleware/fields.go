@@ -0,0 +1,141 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const fieldsParam = "fields"
+
+// fieldTree is a parsed ?fields= selection. A nil subtree keeps the whole
+// value under that field.
+type fieldTree map[string]fieldTree
+
+// Fields prunes JSON responses to the comma-separated fields named in the
+// ?fields= query parameter, e.g. ?fields=id,name,profile.avatar. Arrays
+// are pruned item by item, and a core.CursorPage prunes its items. Field
+// names are matched regardless of case style, so the selection works
+// whether or not Serializer renames keys. Error responses are left whole.
+// Fields may be registered before or after Envelope: an enveloped body has
+// its data pruned and its meta kept.
+func Fields() gin.HandlerFunc {
+	return interceptJSON(func(c *gin.Context, status int, body interface{}) interface{} {
+		raw := c.Query(fieldsParam)
+		if raw == "" || status >= http.StatusBadRequest {
+			return body
+		}
+		selection := parseFields(raw)
+		if envelope, ok := body.(map[string]interface{}); ok && c.GetBool(envelopedKey) {
+			envelope["data"] = selection.prune(envelope["data"])
+			return envelope
+		}
+		if page, ok := body.(map[string]interface{}); ok && isCursorPage(page) {
+			page["items"] = selection.prune(page["items"])
+			return page
+		}
+		return selection.prune(body)
+	})
+}
+
+// AllowFields limits the fields a route may be asked for. Requests naming
+// anything else are rejected with 400 before the handler runs. Nested
+// fields are allowed by their path, or by allowing their parent.
+func AllowFields(allowed ...string) gin.HandlerFunc {
+	allowlist := parseFields(strings.Join(allowed, ","))
+	return func(c *gin.Context) {
+		raw := c.Query(fieldsParam)
+		if raw == "" {
+			c.Next()
+			return
+		}
+		if field, ok := allowlist.allows(parseFields(raw), ""); !ok {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "field not selectable: " + field})
+			return
+		}
+		c.Next()
+	}
+}
+
+func parseFields(raw string) fieldTree {
+	tree := fieldTree{}
+	for _, path := range strings.Split(raw, ",") {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+		node := tree
+		parts := strings.Split(path, ".")
+		for i, part := range parts {
+			key := fieldKey(part)
+			child, exists := node[key]
+			if i == len(parts)-1 {
+				// Selecting a field whole overrides a nested selection
+				node[key] = nil
+				break
+			}
+			if exists && child == nil {
+				break
+			}
+			if !exists {
+				child = fieldTree{}
+				node[key] = child
+			}
+			node = child
+		}
+	}
+	return tree
+}
+
+// fieldKey normalizes a field name so userId, user_id and UserID match
+func fieldKey(name string) string {
+	return SnakeCase(name)
+}
+
+func (t fieldTree) prune(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(t))
+		for key, field := range v {
+			sub, selected := t[fieldKey(key)]
+			if !selected {
+				continue
+			}
+			if sub != nil {
+				field = sub.prune(field)
+			}
+			out[key] = field
+		}
+		return out
+	case []interface{}:
+		for i, item := range v {
+			v[i] = t.prune(item)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// allows reports whether every path in requested is covered by t, and the
+// first one that is not
+func (t fieldTree) allows(requested fieldTree, prefix string) (string, bool) {
+	for key, sub := range requested {
+		allowedSub, exists := t[key]
+		if !exists {
+			return prefix + key, false
+		}
+		if allowedSub == nil {
+			continue
+		}
+		if sub == nil {
+			// The whole field was asked for but only parts are allowed
+			return prefix + key, false
+		}
+		if field, ok := allowedSub.allows(sub, prefix+key+"."); !ok {
+			return field, false
+		}
+	}
+	return "", true
+}
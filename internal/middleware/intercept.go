@@ -0,0 +1,144 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// transformJSON rewrites a decoded JSON response body. Objects decode to
+// map[string]interface{} and numbers to json.Number, so values round-trip
+// without losing precision.
+type transformJSON func(c *gin.Context, status int, body interface{}) interface{}
+
+// interceptJSON buffers JSON responses so transform can rewrite them after
+// the handler ran. Other responses, and responses that are flushed while
+// streaming, pass through untouched.
+func interceptJSON(transform transformJSON) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		w := &bufferedWriter{ResponseWriter: c.Writer, status: http.StatusOK}
+		c.Writer = w
+		c.Next()
+		c.Writer = w.ResponseWriter
+
+		if w.passthrough {
+			return
+		}
+		if !w.written {
+			if w.statusSet {
+				w.ResponseWriter.WriteHeader(w.status)
+			}
+			return
+		}
+
+		body := w.buf.Bytes()
+		if isJSON(w.Header().Get("Content-Type")) && len(body) > 0 {
+			if rewritten, ok := rewriteJSON(c, w.status, body, transform); ok {
+				body = rewritten
+				w.Header().Del("Content-Length")
+			}
+		}
+		w.ResponseWriter.WriteHeader(w.status)
+		w.ResponseWriter.Write(body)
+	}
+}
+
+func rewriteJSON(c *gin.Context, status int, body []byte, transform transformJSON) ([]byte, bool) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil || decoder.More() {
+		return nil, false
+	}
+
+	var out bytes.Buffer
+	encoder := json.NewEncoder(&out)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(transform(c, status, value)); err != nil {
+		return nil, false
+	}
+	return bytes.TrimSuffix(out.Bytes(), []byte("\n")), true
+}
+
+func isJSON(contentType string) bool {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	mediaType = strings.TrimSpace(mediaType)
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// bufferedWriter holds the response until the interceptor has seen it
+type bufferedWriter struct {
+	gin.ResponseWriter
+	buf         bytes.Buffer
+	status      int
+	statusSet   bool
+	written     bool
+	passthrough bool
+}
+
+func (w *bufferedWriter) WriteHeader(status int) {
+	if w.passthrough {
+		w.ResponseWriter.WriteHeader(status)
+		return
+	}
+	if !w.written {
+		w.status, w.statusSet = status, true
+	}
+}
+
+func (w *bufferedWriter) WriteHeaderNow() {
+	if w.passthrough {
+		w.ResponseWriter.WriteHeaderNow()
+		return
+	}
+	w.written = true
+}
+
+func (w *bufferedWriter) Write(data []byte) (int, error) {
+	if w.passthrough {
+		return w.ResponseWriter.Write(data)
+	}
+	w.written = true
+	return w.buf.Write(data)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *bufferedWriter) Status() int {
+	if w.passthrough {
+		return w.ResponseWriter.Status()
+	}
+	return w.status
+}
+
+func (w *bufferedWriter) Written() bool {
+	return w.passthrough || w.written
+}
+
+func (w *bufferedWriter) Size() int {
+	if w.passthrough {
+		return w.ResponseWriter.Size()
+	}
+	if !w.written {
+		return -1
+	}
+	return w.buf.Len()
+}
+
+// Flush means the handler is streaming: send what is buffered and stop
+// intercepting
+func (w *bufferedWriter) Flush() {
+	if !w.passthrough {
+		w.passthrough = true
+		w.ResponseWriter.WriteHeader(w.status)
+		w.ResponseWriter.Write(w.buf.Bytes())
+		w.buf.Reset()
+	}
+	w.ResponseWriter.Flush()
+}
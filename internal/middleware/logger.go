@@ -1,9 +1,9 @@
 package middleware
 
 import (
-	"log"
 	"time"
 
+	"github.com/calummacc/goblin/internal/core"
 	"github.com/gin-gonic/gin"
 )
 
@@ -22,7 +22,7 @@ func Logger() gin.HandlerFunc {
 			path = path + "?" + raw
 		}
 
-		log.Printf("[GIN] %3d | %13v | %15s | %-7s %s",
+		core.RequestLogger(c).Infof("[GIN] %3d | %13v | %15s | %-7s %s",
 			status,
 			latency,
 			c.ClientIP(),
@@ -32,24 +32,18 @@ func Logger() gin.HandlerFunc {
 	}
 }
 
-func RequestID() gin.HandlerFunc {
+// RequestID tags each request with an ID from generator, or a UUIDv7 when
+// none is given
+func RequestID(generator ...core.IDGenerator) gin.HandlerFunc {
+	ids := core.NewUUIDv7Generator(nil)
+	if len(generator) > 0 && generator[0] != nil {
+		ids = generator[0]
+	}
+
 	return func(c *gin.Context) {
-		requestID := generateUUID()
+		requestID := ids.NewID()
 		c.Writer.Header().Set("X-Request-ID", requestID)
 		c.Set("RequestID", requestID)
 		c.Next()
 	}
 }
-
-func generateUUID() string {
-	return time.Now().Format("20060102150405") + "-" + randomString(6)
-}
-
-func randomString(n int) string {
-	const letters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
-	b := make([]byte, n)
-	for i := range b {
-		b[i] = letters[time.Now().UnixNano()%int64(len(letters))]
-	}
-	return string(b)
-}
@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"net/http"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MaintenanceMode makes routes answer 503 while enabled. Paths under an
+// allowed prefix, matched by whole segments, and routes using SkipMaintenance keep being served.
+type MaintenanceMode struct {
+	enabled    atomic.Bool
+	retryAfter time.Duration
+
+	mu    sync.RWMutex
+	allow []string
+}
+
+func NewMaintenanceMode(retryAfter time.Duration, allow ...string) *MaintenanceMode {
+	return &MaintenanceMode{
+		retryAfter: retryAfter,
+		allow:      allow,
+	}
+}
+
+func (m *MaintenanceMode) Enable() {
+	m.enabled.Store(true)
+}
+
+func (m *MaintenanceMode) Disable() {
+	m.enabled.Store(false)
+}
+
+func (m *MaintenanceMode) Enabled() bool {
+	return m.enabled.Load()
+}
+
+// Allow adds path prefixes that are served during maintenance, e.g.
+// "/admin" for /admin and everything below it
+func (m *MaintenanceMode) Allow(prefixes ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.allow = append(m.allow, prefixes...)
+}
+
+// Middleware should be mounted on the engine before any other middleware
+// so rejected requests do no further work.
+func (m *MaintenanceMode) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !m.Enabled() || m.allowed(c) {
+			c.Next()
+			return
+		}
+
+		if m.retryAfter > 0 {
+			c.Header("Retry-After", strconv.Itoa(int(m.retryAfter.Seconds())))
+		}
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Service Unavailable",
+		})
+	}
+}
+
+func (m *MaintenanceMode) allowed(c *gin.Context) bool {
+	for _, name := range c.HandlerNames() {
+		if name == skipMaintenanceName {
+			return true
+		}
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	path := c.Request.URL.Path
+	for _, prefix := range m.allow {
+		if underPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// underPrefix matches whole path segments, so "/admin" allows "/admin" and
+// "/admin/users" but not "/administrator"
+func underPrefix(path, prefix string) bool {
+	prefix = strings.TrimSuffix(prefix, "/")
+	return path == prefix || strings.HasPrefix(path, prefix+"/")
+}
+
+// SkipMaintenance opts a route or group out of maintenance mode
+func SkipMaintenance() gin.HandlerFunc {
+	return skipMaintenance
+}
+
+func skipMaintenance(c *gin.Context) {
+	c.Next()
+}
+
+var skipMaintenanceName = runtime.FuncForPC(reflect.ValueOf(skipMaintenance).Pointer()).Name()
@@ -1,11 +1,12 @@
 package middleware
 
 import (
+	"errors"
 	"fmt"
-	"log"
 	"net/http"
 	"runtime/debug"
 
+	"github.com/calummacc/goblin/internal/core"
 	"github.com/gin-gonic/gin"
 )
 
@@ -14,7 +15,7 @@ func Recovery() gin.HandlerFunc {
 		defer func() {
 			if err := recover(); err != nil {
 				stack := debug.Stack()
-				log.Printf("PANIC: %v\n%s", err, string(stack))
+				core.RequestLogger(c).Errorf("PANIC: %v\n%s", err, string(stack))
 
 				requestID, exists := c.Get("RequestID")
 				errorID := "unknown"
@@ -43,13 +44,20 @@ func ErrorHandler() gin.HandlerFunc {
 		if len(c.Errors) > 0 {
 			err := c.Errors.Last()
 
+			// Guard rejections raised from handlers keep their status
+			var guardErr *core.GuardError
+			if errors.As(err.Err, &guardErr) {
+				core.AbortWithGuardError(c, guardErr)
+				return
+			}
+
 			requestID, exists := c.Get("RequestID")
 			errorID := "unknown"
 			if exists {
 				errorID = requestID.(string)
 			}
 
-			log.Printf("Error: %v", err.Err)
+			core.RequestLogger(c).Errorf("Error: %v", err.Err)
 
 			c.JSON(http.StatusInternalServerError, gin.H{
 				"error":    "Internal Server Error",
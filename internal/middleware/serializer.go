@@ -0,0 +1,275 @@
+package middleware
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FieldNaming is the case JSON object keys are written in
+type FieldNaming int
+
+const (
+	NamingAsIs FieldNaming = iota // Keep the keys the handler produced
+	NamingCamelCase
+	NamingSnakeCase
+)
+
+type SerializerOptions struct {
+	Naming     FieldNaming
+	OmitEmpty  bool   // Drop null, "" and empty array or object fields
+	TimeFormat string // Layout JSON writes time.Time values in, "" keeps RFC 3339
+}
+
+const serializerKey = "goblin.serializer"
+
+// Serializer applies application-wide output settings to every JSON
+// response, so handlers can render their structs as they are and the API
+// stays consistent. Types that need a specific encoding, such as decimals
+// or UUIDs, should implement json.Marshaler; the serializer only rewrites
+// keys and empty fields. Timestamps are only known as such before they are
+// marshaled, so TimeFormat applies to responses rendered with JSON.
+func Serializer(options SerializerOptions) gin.HandlerFunc {
+	if options.Naming == NamingAsIs && !options.OmitEmpty && options.TimeFormat == "" {
+		return func(c *gin.Context) { c.Next() }
+	}
+	intercept := interceptJSON(func(c *gin.Context, status int, body interface{}) interface{} {
+		return options.serialize(body)
+	})
+	return func(c *gin.Context) {
+		c.Set(serializerKey, options)
+		intercept(c)
+	}
+}
+
+// JSON renders value like c.JSON, writing its time.Time values in the
+// TimeFormat of the Serializer the route runs behind
+func JSON(c *gin.Context, status int, value interface{}) {
+	if options, exists := c.Get(serializerKey); exists {
+		if layout := options.(SerializerOptions).TimeFormat; layout != "" {
+			value = formatTimes(reflect.ValueOf(value), layout)
+		}
+	}
+	c.JSON(status, value)
+}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// formatTimes copies v into maps and slices the way encoding/json would
+// write it, with time.Time values formatted in layout. Values marshaling
+// themselves are kept as they are.
+func formatTimes(v reflect.Value, layout string) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+	if v.Type() == timeType {
+		return v.Interface().(time.Time).Format(layout)
+	}
+	if v.Type().Implements(jsonMarshalerType) || v.Type().Implements(textMarshalerType) {
+		return v.Interface()
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return formatTimes(v.Elem(), layout)
+	case reflect.Struct:
+		out := map[string]interface{}{}
+		formatFields(v, layout, out)
+		return out
+	case reflect.Map:
+		if v.IsNil() || v.Type().Key().Kind() != reflect.String {
+			return v.Interface()
+		}
+		out := make(map[string]interface{}, v.Len())
+		for iter := v.MapRange(); iter.Next(); {
+			out[iter.Key().String()] = formatTimes(iter.Value(), layout)
+		}
+		return out
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 || (v.Kind() == reflect.Slice && v.IsNil()) {
+			return v.Interface()
+		}
+		out := make([]interface{}, v.Len())
+		for i := range out {
+			out[i] = formatTimes(v.Index(i), layout)
+		}
+		return out
+	default:
+		return v.Interface()
+	}
+}
+
+// formatFields adds the fields of struct v to out under their JSON names,
+// promoting the fields of embedded structs without overriding outer ones
+func formatFields(v reflect.Value, layout string, out map[string]interface{}) {
+	var embedded []reflect.Value
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, flags, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" {
+			value := v.Field(i)
+			if value.Kind() == reflect.Pointer {
+				if value.IsNil() {
+					continue
+				}
+				value = value.Elem()
+			}
+			if value.Kind() == reflect.Struct {
+				embedded = append(embedded, value)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if strings.Contains(","+flags+",", ",omitempty,") && omitted(v.Field(i)) {
+			continue
+		}
+		out[name] = formatTimes(v.Field(i), layout)
+	}
+
+	for _, value := range embedded {
+		promoted := map[string]interface{}{}
+		formatFields(value, layout, promoted)
+		for name, field := range promoted {
+			if _, exists := out[name]; !exists {
+				out[name] = field
+			}
+		}
+	}
+}
+
+// omitted reports whether encoding/json leaves an omitempty field out
+func omitted(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Struct:
+		return false
+	}
+	return v.IsZero()
+}
+
+func (o SerializerOptions) serialize(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, field := range v {
+			field = o.serialize(field)
+			if o.OmitEmpty && empty(field) {
+				continue
+			}
+			out[o.rename(key)] = field
+		}
+		return out
+	case []interface{}:
+		for i, item := range v {
+			v[i] = o.serialize(item)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+func (o SerializerOptions) rename(key string) string {
+	switch o.Naming {
+	case NamingCamelCase:
+		return CamelCase(key)
+	case NamingSnakeCase:
+		return SnakeCase(key)
+	default:
+		return key
+	}
+}
+
+func empty(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	default:
+		return false
+	}
+}
+
+// CamelCase converts snake_case, kebab-case and PascalCase keys to camelCase
+func CamelCase(key string) string {
+	words := splitWords(key)
+	for i, word := range words {
+		if i == 0 {
+			words[i] = strings.ToLower(word)
+			continue
+		}
+		first, size := utf8.DecodeRuneInString(word)
+		words[i] = string(unicode.ToUpper(first)) + strings.ToLower(word[size:])
+	}
+	return strings.Join(words, "")
+}
+
+// SnakeCase converts camelCase, PascalCase and kebab-case keys to snake_case
+func SnakeCase(key string) string {
+	words := splitWords(key)
+	for i, word := range words {
+		words[i] = strings.ToLower(word)
+	}
+	return strings.Join(words, "_")
+}
+
+// splitWords splits a key on separators and case changes, keeping
+// acronyms together: "userID" and "user_id" both give [user ID].
+func splitWords(key string) []string {
+	var (
+		words []string
+		word  []rune
+	)
+	runes := []rune(key)
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, string(word))
+			word = word[:0]
+		}
+	}
+
+	for i, r := range runes {
+		switch {
+		case r == '_' || r == '-' || r == ' ' || r == '.':
+			flush()
+			continue
+		case unicode.IsUpper(r) && i > 0:
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				flush()
+			}
+		}
+		word = append(word, r)
+	}
+	flush()
+	return words
+}
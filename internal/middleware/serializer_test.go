@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestCamelCase(t *testing.T) {
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"user_id", "userId"},
+		{"created-at", "createdAt"},
+		{"UserName", "userName"},
+		{"straße_ärger", "straßeÄrger"},
+		{"über_élan", "überÉlan"},
+	}
+	for _, tt := range tests {
+		if got := CamelCase(tt.key); got != tt.want {
+			t.Errorf("CamelCase(%q) = %q, want %q", tt.key, got, tt.want)
+		}
+	}
+}
+
+type auditFields struct {
+	CreatedAt time.Time  `json:"created_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+type article struct {
+	auditFields
+	Title    string      `json:"title"`
+	Release  string      `json:"release"`
+	Versions []time.Time `json:"versions"`
+	secret   time.Time
+}
+
+func TestTimeFormatAppliesToTimeValues(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	at := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+	engine := gin.New()
+	engine.Use(Serializer(SerializerOptions{TimeFormat: time.DateOnly}))
+	engine.GET("/article", func(c *gin.Context) {
+		JSON(c, http.StatusOK, article{
+			auditFields: auditFields{CreatedAt: at},
+			Title:       "Launch",
+			// Looks like a timestamp but is a string, so it is kept as is
+			Release:  "2024-03-01T09:30:00Z",
+			Versions: []time.Time{at, at.AddDate(0, 0, 1)},
+			secret:   at,
+		})
+	})
+
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/article", nil))
+	want := `{"created_at":"2024-03-01","release":"2024-03-01T09:30:00Z","title":"Launch","versions":["2024-03-01","2024-03-02"]}`
+	if got := rec.Body.String(); got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
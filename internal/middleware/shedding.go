@@ -0,0 +1,149 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LoadShedderOptions struct {
+	MaxInFlight  int           // Requests served concurrently, 0 means unlimited
+	MaxQueue     int           // Requests allowed to wait for a free slot
+	QueueTimeout time.Duration // Longest a queued request waits for a slot
+	MaxLatency   time.Duration // Shed while the average latency is above this, 0 disables
+	Exempt       []string      // Path prefixes that are never shed (health, admin), matched by whole segments
+	RetryAfter   time.Duration // Sent as Retry-After with rejections, defaults to one second
+}
+
+// LoadShedder rejects excess traffic with 503 instead of letting latency
+// grow without bound.
+type LoadShedder struct {
+	options LoadShedderOptions
+	slots   chan struct{}
+
+	inFlight atomic.Int64
+	queued   atomic.Int64
+	latency  atomic.Int64 // moving average in nanoseconds
+	shed     atomic.Uint64
+}
+
+func NewLoadShedder(options LoadShedderOptions) *LoadShedder {
+	l := &LoadShedder{options: options}
+	if options.MaxInFlight > 0 {
+		l.slots = make(chan struct{}, options.MaxInFlight)
+	}
+	return l
+}
+
+// InFlight returns the number of requests currently being served
+func (l *LoadShedder) InFlight() int {
+	return int(l.inFlight.Load())
+}
+
+// Shed returns the number of requests rejected so far
+func (l *LoadShedder) Shed() uint64 {
+	return l.shed.Load()
+}
+
+func (l *LoadShedder) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if l.exempt(c.Request.URL.Path) {
+			c.Next()
+			return
+		}
+
+		if max := l.options.MaxLatency; max > 0 && l.inFlight.Load() > 0 &&
+			time.Duration(l.latency.Load()) > max {
+			l.reject(c)
+			return
+		}
+
+		if !l.acquire(c) {
+			l.reject(c)
+			return
+		}
+		defer l.release()
+
+		start := time.Now()
+		c.Next()
+		l.observe(time.Since(start))
+	}
+}
+
+func (l *LoadShedder) acquire(c *gin.Context) bool {
+	if l.slots == nil {
+		l.inFlight.Add(1)
+		return true
+	}
+
+	select {
+	case l.slots <- struct{}{}:
+		l.inFlight.Add(1)
+		return true
+	default:
+	}
+
+	if l.queued.Add(1) > int64(l.options.MaxQueue) {
+		l.queued.Add(-1)
+		return false
+	}
+	defer l.queued.Add(-1)
+
+	var timeout <-chan time.Time
+	if l.options.QueueTimeout > 0 {
+		timer := time.NewTimer(l.options.QueueTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case l.slots <- struct{}{}:
+		l.inFlight.Add(1)
+		return true
+	case <-timeout:
+		return false
+	case <-c.Request.Context().Done():
+		return false
+	}
+}
+
+func (l *LoadShedder) release() {
+	l.inFlight.Add(-1)
+	if l.slots != nil {
+		<-l.slots
+	}
+}
+
+// observe folds a sample into the moving average with a weight of 1/8
+func (l *LoadShedder) observe(latency time.Duration) {
+	old := l.latency.Load()
+	l.latency.Store(old + (int64(latency)-old)/8)
+}
+
+func (l *LoadShedder) reject(c *gin.Context) {
+	l.shed.Add(1)
+	c.Header("Retry-After", strconv.Itoa(retryAfterSeconds(l.options.RetryAfter)))
+	c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+		"error": "Service Unavailable",
+	})
+}
+
+func (l *LoadShedder) exempt(path string) bool {
+	for _, prefix := range l.options.Exempt {
+		if underPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// retryAfterSeconds rounds d up to whole seconds, at least one
+func retryAfterSeconds(d time.Duration) int {
+	if seconds := int((d + time.Second - 1) / time.Second); seconds > 1 {
+		return seconds
+	}
+	return 1
+}
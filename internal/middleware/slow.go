@@ -0,0 +1,183 @@
+package middleware
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/calummacc/goblin/internal/core"
+	"github.com/gin-gonic/gin"
+)
+
+type SlowRequestOptions struct {
+	Threshold  time.Duration // Requests taking longer are dumped
+	SampleRate float64       // Fraction of slow requests dumped, 0 dumps all
+	MaxPerMin  int           // Dumps logged per minute at most, 0 means unlimited
+	Stacks     bool          // Dump the handler's stack if it is still running at the threshold
+	Fields     []string      // Context keys logged with each dump
+}
+
+// SlowRequestDetector logs requests that exceed a threshold together with
+// their pipeline timing, so slow paths can be diagnosed from logs. Timing
+// is only available with core.WithProfiling.
+type SlowRequestDetector struct {
+	options SlowRequestOptions
+	slow    atomic.Uint64
+
+	mu          sync.Mutex
+	windowStart time.Time
+	windowDumps int
+}
+
+func NewSlowRequestDetector(options SlowRequestOptions) *SlowRequestDetector {
+	if options.Fields == nil {
+		options.Fields = []string{"RequestID"}
+	}
+	return &SlowRequestDetector{options: options}
+}
+
+// Slow returns the number of slow requests seen so far, dumped or not
+func (d *SlowRequestDetector) Slow() uint64 {
+	return d.slow.Load()
+}
+
+func (d *SlowRequestDetector) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		// Decide once whether this request is dumped, either when it crosses
+		// the threshold while running or when it finishes
+		var (
+			once    sync.Once
+			sampled bool
+			stack   []byte
+		)
+		decide := func(running bool, goroutine int64) {
+			once.Do(func() {
+				sampled = d.sample()
+				if sampled && running && d.options.Stacks {
+					stack = goroutineStack(goroutine)
+				}
+			})
+		}
+
+		goroutine := int64(-1)
+		if d.options.Stacks {
+			goroutine = currentGoroutine()
+		}
+		timer := time.AfterFunc(d.options.Threshold, func() { decide(true, goroutine) })
+
+		c.Next()
+
+		timer.Stop()
+		duration := time.Since(start)
+		if duration < d.options.Threshold {
+			return
+		}
+		d.slow.Add(1)
+
+		decide(false, goroutine)
+		if sampled {
+			d.dump(c, duration, stack)
+		}
+	}
+}
+
+// sample applies the sample rate and the per-minute cap
+func (d *SlowRequestDetector) sample() bool {
+	if rate := d.options.SampleRate; rate > 0 && rand.Float64() >= rate {
+		return false
+	}
+	if d.options.MaxPerMin <= 0 {
+		return true
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if now := time.Now(); now.Sub(d.windowStart) >= time.Minute {
+		d.windowStart = now
+		d.windowDumps = 0
+	}
+	if d.windowDumps >= d.options.MaxPerMin {
+		return false
+	}
+	d.windowDumps++
+	return true
+}
+
+func (d *SlowRequestDetector) dump(c *gin.Context, duration time.Duration, stack []byte) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Slow request: %s %s took %v (threshold %v) status=%d",
+		c.Request.Method, c.Request.URL.Path, duration, d.options.Threshold, c.Writer.Status())
+
+	for _, field := range d.options.Fields {
+		if value, exists := c.Get(field); exists {
+			fmt.Fprintf(&b, " %s=%v", field, value)
+		}
+	}
+
+	if timings := core.StageTimings(c); len(timings) > 0 {
+		names := make([]string, 0, len(timings))
+		for name := range timings {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		b.WriteString("\n  stages:")
+		for _, name := range names {
+			fmt.Fprintf(&b, " %s=%v", name, timings[name])
+		}
+	}
+
+	if len(stack) > 0 {
+		b.WriteString("\n  handler stack at threshold:\n")
+		b.Write(stack)
+	}
+	core.RequestLogger(c).Warnf("%s", b.String())
+}
+
+// currentGoroutine parses the calling goroutine's ID from its stack header
+func currentGoroutine() int64 {
+	buf := make([]byte, 64)
+	buf = buf[:runtime.Stack(buf, false)]
+	buf = bytes.TrimPrefix(buf, []byte("goroutine "))
+	if i := bytes.IndexByte(buf, ' '); i > 0 {
+		if id, err := strconv.ParseInt(string(buf[:i]), 10, 64); err == nil {
+			return id
+		}
+	}
+	return -1
+}
+
+// goroutineStack returns the stack of goroutine id, or nil if it is gone
+func goroutineStack(id int64) []byte {
+	if id < 0 {
+		return nil
+	}
+
+	buf := make([]byte, 64<<10)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+
+	header := []byte(fmt.Sprintf("goroutine %d [", id))
+	for _, section := range bytes.Split(buf, []byte("\n\n")) {
+		if bytes.HasPrefix(section, header) {
+			return section
+		}
+	}
+	return nil
+}
@@ -0,0 +1,268 @@
+package openapi
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/calummacc/goblin/internal/core"
+	"github.com/gin-gonic/gin"
+)
+
+type Options struct {
+	Path      string // Where the spec is served
+	Title     string
+	Version   string
+	SwaggerUI string // Where Swagger UI is served, "" disables it
+}
+
+var defaultOptions = Options{
+	Path:    "/openapi.json",
+	Title:   "API",
+	Version: "1.0.0",
+}
+
+func WithPath(path string) func(*Options) {
+	return func(opts *Options) {
+		opts.Path = path
+	}
+}
+
+func WithInfo(title, version string) func(*Options) {
+	return func(opts *Options) {
+		opts.Title = title
+		opts.Version = version
+	}
+}
+
+// WithSwaggerUI serves Swagger UI for the spec at path, e.g. "/docs". The
+// UI assets are loaded from a CDN.
+func WithSwaggerUI(path string) func(*Options) {
+	return func(opts *Options) {
+		opts.SwaggerUI = path
+	}
+}
+
+// Operation documents a route beyond what the route table says
+type Operation struct {
+	Summary     string
+	Description string
+	Tags        []string
+	Deprecated  bool
+	Query       interface{}         // Struct whose fields are query parameters, by `form` tag
+	Request     interface{}         // Request body, e.g. CreateUserRequest{}
+	Responses   map[int]interface{} // Response bodies by status, nil for no body
+}
+
+// OpenAPIModule serves an OpenAPI 3 spec of every registered route. Routes
+// are documented from the route table, with path parameters and the
+// handler as operationId, and from the Operations registered with
+// Describe, whose DTOs are described from their json and binding tags.
+type OpenAPIModule struct {
+	core.BaseModule
+	app     *core.Application
+	options Options
+
+	mu         sync.RWMutex
+	operations map[string]Operation
+}
+
+func NewOpenAPIModule(app *core.Application, opts ...func(*Options)) *OpenAPIModule {
+	options := defaultOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return &OpenAPIModule{app: app, options: options, operations: make(map[string]Operation)}
+}
+
+// Describe documents route, given as "METHOD /path/:param"
+func (m *OpenAPIModule) Describe(route string, operation Operation) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.operations[route] = operation
+}
+
+func (m *OpenAPIModule) RegisterRoutes(router *gin.RouterGroup) {
+	router.GET(m.options.Path, m.getSpec)
+	if m.options.SwaggerUI != "" {
+		router.GET(m.options.SwaggerUI, m.getSwaggerUI)
+	}
+}
+
+func (m *OpenAPIModule) getSpec(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, m.Spec())
+}
+
+// Spec builds the OpenAPI document for the routes registered so far
+func (m *OpenAPIModule) Spec() map[string]interface{} {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	components := &schemas{components: make(map[string]Schema)}
+	paths := make(map[string]map[string]interface{})
+
+	routes := m.app.GetEngine().Routes()
+	sort.Slice(routes, func(i, j int) bool { return routes[i].Path < routes[j].Path })
+	for _, route := range routes {
+		if route.Path == m.options.Path || route.Path == m.options.SwaggerUI {
+			continue
+		}
+
+		path, parameters := openAPIPath(route.Path)
+		if paths[path] == nil {
+			paths[path] = make(map[string]interface{})
+		}
+		key := route.Method + " " + route.Path
+		paths[path][strings.ToLower(route.Method)] = m.operation(key, route.Handler, parameters, components)
+	}
+
+	spec := map[string]interface{}{
+		"openapi": "3.0.3",
+		"info":    map[string]string{"title": m.options.Title, "version": m.options.Version},
+		"paths":   paths,
+	}
+	if len(components.components) > 0 {
+		spec["components"] = map[string]interface{}{"schemas": components.components}
+	}
+	return spec
+}
+
+func (m *OpenAPIModule) operation(key, handler string, parameters []Schema, components *schemas) map[string]interface{} {
+	described, exists := m.operations[key]
+	operation := map[string]interface{}{
+		"operationId": operationID(handler),
+	}
+
+	if described.Query != nil {
+		parameters = append(parameters, queryParameters(reflect.TypeOf(described.Query), components)...)
+	}
+	if len(parameters) > 0 {
+		operation["parameters"] = parameters
+	}
+
+	if described.Summary != "" {
+		operation["summary"] = described.Summary
+	}
+	if described.Description != "" {
+		operation["description"] = described.Description
+	}
+	if len(described.Tags) > 0 {
+		operation["tags"] = described.Tags
+	}
+	if described.Deprecated {
+		operation["deprecated"] = true
+	}
+	if described.Request != nil {
+		operation["requestBody"] = map[string]interface{}{
+			"required": true,
+			"content":  jsonContent(components.of(reflect.TypeOf(described.Request))),
+		}
+	}
+
+	responses := make(map[string]interface{})
+	for status, body := range described.Responses {
+		response := map[string]interface{}{"description": http.StatusText(status)}
+		if body != nil {
+			response["content"] = jsonContent(components.of(reflect.TypeOf(body)))
+		}
+		responses[strconv.Itoa(status)] = response
+	}
+	if !exists || len(responses) == 0 {
+		responses["default"] = map[string]interface{}{"description": "Response"}
+	}
+	operation["responses"] = responses
+	return operation
+}
+
+func jsonContent(schema Schema) map[string]interface{} {
+	return map[string]interface{}{"application/json": map[string]interface{}{"schema": schema}}
+}
+
+// openAPIPath turns "/users/:id" into "/users/{id}" with its parameters
+func openAPIPath(path string) (string, []Schema) {
+	segments := strings.Split(path, "/")
+	var parameters []Schema
+	for i, segment := range segments {
+		if len(segment) < 2 || (segment[0] != ':' && segment[0] != '*') {
+			continue
+		}
+		name := segment[1:]
+		segments[i] = "{" + name + "}"
+		parameters = append(parameters, Schema{
+			"name":     name,
+			"in":       "path",
+			"required": true,
+			"schema":   Schema{"type": "string"},
+		})
+	}
+	return strings.Join(segments, "/"), parameters
+}
+
+func queryParameters(t reflect.Type, components *schemas) []Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var parameters []Schema
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("form"), ",")
+		if !field.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		rules := strings.Split(field.Tag.Get("binding"), ",")
+		parameter := Schema{
+			"name":   name,
+			"in":     "query",
+			"schema": components.field(field, rules),
+		}
+		if contains(rules, "required") {
+			parameter["required"] = true
+		}
+		if description := field.Tag.Get("description"); description != "" {
+			parameter["description"] = description
+		}
+		parameters = append(parameters, parameter)
+	}
+	return parameters
+}
+
+// operationID derives an ID from the handler name, e.g.
+// "user.(*Controller).GetUser-fm" gives "user.Controller.GetUser"
+func operationID(handler string) string {
+	if i := strings.LastIndex(handler, "/"); i >= 0 {
+		handler = handler[i+1:]
+	}
+	handler = strings.TrimSuffix(handler, "-fm")
+	return strings.NewReplacer("(*", "", "(", "", ")", "").Replace(handler)
+}
+
+func (m *OpenAPIModule) getSwaggerUI(ctx *gin.Context) {
+	ctx.Data(http.StatusOK, "text/html; charset=utf-8", []byte(fmt.Sprintf(swaggerUIPage, html.EscapeString(m.options.Title), m.options.Path)))
+}
+
+const swaggerUIPage = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>%s</title>
+<link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+<div id="swagger-ui"></div>
+<script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+<script>SwaggerUIBundle({url: %q, dom_id: "#swagger-ui"});</script>
+</body>
+</html>
+`
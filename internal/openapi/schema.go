@@ -0,0 +1,332 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schema is an OpenAPI schema object
+type Schema map[string]interface{}
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// schemas builds schemas from Go types, collecting named structs as
+// components referenced with $ref
+type schemas struct {
+	components map[string]Schema
+}
+
+func (s *schemas) of(t reflect.Type) Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return Schema{"type": "string", "format": "date-time"}
+	case t.Implements(marshalerType) || reflect.PointerTo(t).Implements(marshalerType):
+		// Custom encodings cannot be inferred
+		return Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return Schema{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return Schema{"type": "integer", "format": "int32"}
+	case reflect.Int64, reflect.Uint, reflect.Uint64, reflect.Uintptr:
+		return Schema{"type": "integer", "format": "int64"}
+	case reflect.Float32:
+		return Schema{"type": "number", "format": "float"}
+	case reflect.Float64:
+		return Schema{"type": "number", "format": "double"}
+	case reflect.String:
+		return Schema{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return Schema{"type": "string", "format": "byte"}
+		}
+		return Schema{"type": "array", "items": s.of(t.Elem())}
+	case reflect.Map:
+		return Schema{"type": "object", "additionalProperties": s.of(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return s.object(t)
+		}
+		name := componentName(t)
+		if _, exists := s.components[name]; !exists {
+			// Reserve the name first so recursive types terminate
+			s.components[name] = Schema{}
+			s.components[name] = s.object(t)
+		}
+		return Schema{"$ref": "#/components/schemas/" + name}
+	default:
+		return Schema{}
+	}
+}
+
+// object describes a struct's JSON fields. Fields are required when their
+// binding rules say so.
+func (s *schemas) object(t reflect.Type) Schema {
+	properties := Schema{}
+	var required []string
+	s.fields(t, properties, &required)
+
+	schema := Schema{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+func (s *schemas) fields(t reflect.Type, properties Schema, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				s.fields(embedded, properties, required)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		rules := strings.Split(field.Tag.Get("binding"), ",")
+		schema := s.field(field, rules)
+		if contains(strings.Split(options, ","), "string") && isScalar(field.Type) {
+			// Encoded as a JSON string, e.g. 64-bit IDs
+			schema = Schema{"type": "string"}
+		}
+		if description := field.Tag.Get("description"); description != "" {
+			schema = withDescription(schema, description)
+		}
+		properties[name] = schema
+
+		if contains(rules, "required") && !strings.Contains(options, "omitempty") {
+			*required = append(*required, name)
+		}
+	}
+}
+
+// field describes a struct field, with the constraints its binding rules
+// put on it as schema keywords. Rules after "dive" apply to the elements.
+func (s *schemas) field(field reflect.StructField, rules []string) Schema {
+	t := field.Type
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	schema := s.of(t)
+
+	for i, rule := range rules {
+		if strings.TrimSpace(rule) != "dive" || (t.Kind() != reflect.Slice && t.Kind() != reflect.Array) {
+			continue
+		}
+		if items, ok := schema["items"].(Schema); ok {
+			schema["items"] = constrain(items, t.Elem(), rules[i+1:])
+		}
+		rules = rules[:i]
+		break
+	}
+	return constrain(schema, t, rules)
+}
+
+var quotedParams = regexp.MustCompile(`'[^']*'|\S+`)
+
+// constrain translates validator rules into schema keywords: min, max and
+// len bound numbers, lengths and item counts, gt, gte, lt and lte bound
+// numbers, oneof gives an enum, and formats and patterns come from rules
+// such as email, uuid, alphanum or startswith. Other rules, and rules
+// combined with "|", have no schema equivalent.
+func constrain(schema Schema, t reflect.Type, rules []string) Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	keywords := Schema{}
+	for _, rule := range rules {
+		rule = strings.TrimSpace(rule)
+		if strings.Contains(rule, "|") {
+			continue
+		}
+		name, param, _ := strings.Cut(rule, "=")
+
+		switch name {
+		case "min", "max", "len":
+			bound, err := strconv.ParseFloat(param, 64)
+			if err != nil {
+				continue
+			}
+			lower, upper := lengthKeywords(t)
+			if name != "max" {
+				keywords[lower] = bound
+			}
+			if name != "min" {
+				keywords[upper] = bound
+			}
+		case "gt", "gte", "lt", "lte":
+			bound, err := strconv.ParseFloat(param, 64)
+			if err != nil || !isNumber(t) {
+				continue
+			}
+			if strings.HasPrefix(name, "g") {
+				keywords["minimum"] = bound
+				if name == "gt" {
+					keywords["exclusiveMinimum"] = true
+				}
+			} else {
+				keywords["maximum"] = bound
+				if name == "lt" {
+					keywords["exclusiveMaximum"] = true
+				}
+			}
+		case "oneof":
+			var values []interface{}
+			for _, value := range quotedParams.FindAllString(param, -1) {
+				values = append(values, enumValue(t, strings.Trim(value, "'")))
+			}
+			keywords["enum"] = values
+		case "email", "uri", "url", "uuid", "hostname", "ipv4", "ipv6":
+			keywords["format"] = formats[name]
+		case "alpha", "alphanum", "numeric", "number", "hexadecimal", "lowercase", "uppercase":
+			keywords["pattern"] = patterns[name]
+		case "startswith":
+			keywords["pattern"] = "^" + regexp.QuoteMeta(param)
+		case "endswith":
+			keywords["pattern"] = regexp.QuoteMeta(param) + "$"
+		case "contains":
+			keywords["pattern"] = regexp.QuoteMeta(param)
+		}
+	}
+
+	if len(keywords) == 0 {
+		return schema
+	}
+	if _, ref := schema["$ref"]; ref {
+		keywords["allOf"] = []Schema{schema}
+		return keywords
+	}
+	for keyword, value := range keywords {
+		schema[keyword] = value
+	}
+	return schema
+}
+
+var formats = map[string]string{
+	"email":    "email",
+	"uri":      "uri",
+	"url":      "uri",
+	"uuid":     "uuid",
+	"hostname": "hostname",
+	"ipv4":     "ipv4",
+	"ipv6":     "ipv6",
+}
+
+var patterns = map[string]string{
+	"alpha":       "^[a-zA-Z]+$",
+	"alphanum":    "^[a-zA-Z0-9]+$",
+	"numeric":     "^[-+]?[0-9]+(?:\\.[0-9]+)?$",
+	"number":      "^[0-9]+$",
+	"hexadecimal": "^(0[xX])?[0-9a-fA-F]+$",
+	"lowercase":   "^[^A-Z]*$",
+	"uppercase":   "^[^a-z]*$",
+}
+
+// lengthKeywords names the bounds min and max put on values of t
+func lengthKeywords(t reflect.Type) (string, string) {
+	switch t.Kind() {
+	case reflect.String:
+		return "minLength", "maxLength"
+	case reflect.Slice, reflect.Array:
+		return "minItems", "maxItems"
+	case reflect.Map:
+		return "minProperties", "maxProperties"
+	}
+	return "minimum", "maximum"
+}
+
+func isNumber(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+func isScalar(t reflect.Type) bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return isNumber(t) || t.Kind() == reflect.Bool || t.Kind() == reflect.String
+}
+
+// enumValue types an oneof value like the field it constrains
+func enumValue(t reflect.Type, value string) interface{} {
+	if isNumber(t) {
+		if number, err := strconv.ParseFloat(value, 64); err == nil {
+			return number
+		}
+	}
+	return value
+}
+
+// withDescription describes a field. $ref siblings are ignored by OpenAPI
+// 3.0, so references are wrapped in allOf.
+func withDescription(schema Schema, description string) Schema {
+	if _, ref := schema["$ref"]; ref {
+		return Schema{"allOf": []Schema{schema}, "description": description}
+	}
+	schema["description"] = description
+	return schema
+}
+
+func componentName(t reflect.Type) string {
+	pkg := t.PkgPath()
+	if i := strings.LastIndex(pkg, "/"); i >= 0 {
+		pkg = pkg[i+1:]
+	}
+	name := t.Name()
+	// Generic instantiations such as CursorPage[github.com/x/user.User]
+	if i := strings.Index(name, "["); i >= 0 {
+		args := name[i+1 : len(name)-1]
+		if j := strings.LastIndex(args, "/"); j >= 0 {
+			args = args[j+1:]
+		}
+		name = name[:i] + "_" + strings.ReplaceAll(args, ".", "_")
+	}
+	if pkg == "" || pkg == "main" {
+		return name
+	}
+	return pkg + "." + name
+}
+
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if strings.TrimSpace(candidate) == value {
+			return true
+		}
+	}
+	return false
+}
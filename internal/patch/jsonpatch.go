@@ -0,0 +1,278 @@
+package patch
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Operation is one step of an RFC 6902 JSON Patch
+type Operation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// JSONPatch applies an RFC 6902 patch to document. The operations apply
+// in order and all or nothing; a failing "test" returns ErrTestFailed so
+// clients can detect that the resource changed under them.
+func JSONPatch(document, patch []byte) ([]byte, error) {
+	target, err := decode(document)
+	if err != nil {
+		return nil, err
+	}
+	var operations []Operation
+	if err := json.Unmarshal(patch, &operations); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+
+	for i, operation := range operations {
+		target, err = operation.apply(target)
+		if err != nil {
+			return nil, fmt.Errorf("operation %d (%s %s): %w", i, operation.Op, operation.Path, err)
+		}
+	}
+	return json.Marshal(target)
+}
+
+func (o Operation) value() (interface{}, error) {
+	if o.Value == nil {
+		return nil, fmt.Errorf("%w: missing value", ErrInvalidPatch)
+	}
+	return decode(o.Value)
+}
+
+func (o Operation) apply(document interface{}) (interface{}, error) {
+	switch o.Op {
+	case "add":
+		value, err := o.value()
+		if err != nil {
+			return nil, err
+		}
+		return add(document, o.Path, value)
+	case "remove":
+		document, _, err := remove(document, o.Path)
+		return document, err
+	case "replace":
+		value, err := o.value()
+		if err != nil {
+			return nil, err
+		}
+		document, _, err = remove(document, o.Path)
+		if err != nil {
+			return nil, err
+		}
+		return add(document, o.Path, value)
+	case "move":
+		if strings.HasPrefix(o.Path, o.From+"/") {
+			return nil, fmt.Errorf("%w: cannot move a value into itself", ErrInvalidPatch)
+		}
+		document, value, err := remove(document, o.From)
+		if err != nil {
+			return nil, err
+		}
+		return add(document, o.Path, value)
+	case "copy":
+		value, err := get(document, o.From)
+		if err != nil {
+			return nil, err
+		}
+		// Round-trip so the copy shares nothing with the original
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		if value, err = decode(data); err != nil {
+			return nil, err
+		}
+		return add(document, o.Path, value)
+	case "test":
+		expected, err := o.value()
+		if err != nil {
+			return nil, err
+		}
+		actual, err := get(document, o.Path)
+		if err != nil {
+			return nil, ErrTestFailed
+		}
+		if !equal(actual, expected) {
+			return nil, ErrTestFailed
+		}
+		return document, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown op %q", ErrInvalidPatch, o.Op)
+	}
+}
+
+// split parses a JSON Pointer (RFC 6901) into unescaped reference tokens
+func split(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("%w: path %q must start with /", ErrInvalidPatch, pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+	}
+	return tokens, nil
+}
+
+func index(token string, length int, appending bool) (int, error) {
+	if appending && token == "-" {
+		return length, nil
+	}
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || i > length || (!appending && i == length) || (token != "0" && strings.HasPrefix(token, "0")) {
+		return 0, fmt.Errorf("%w: invalid array index %q", ErrInvalidPatch, token)
+	}
+	return i, nil
+}
+
+func get(document interface{}, pointer string) (interface{}, error) {
+	tokens, err := split(pointer)
+	if err != nil {
+		return nil, err
+	}
+	for _, token := range tokens {
+		switch node := document.(type) {
+		case map[string]interface{}:
+			value, exists := node[token]
+			if !exists {
+				return nil, fmt.Errorf("%w: %s not found", ErrInvalidPatch, pointer)
+			}
+			document = value
+		case []interface{}:
+			i, err := index(token, len(node), false)
+			if err != nil {
+				return nil, err
+			}
+			document = node[i]
+		default:
+			return nil, fmt.Errorf("%w: %s not found", ErrInvalidPatch, pointer)
+		}
+	}
+	return document, nil
+}
+
+// add sets the value at pointer, inserting into arrays, and returns the
+// new document
+func add(document interface{}, pointer string, value interface{}) (interface{}, error) {
+	tokens, err := split(pointer)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return value, nil
+	}
+	return update(document, tokens, pointer, func(parent interface{}, token string) (interface{}, error) {
+		switch node := parent.(type) {
+		case map[string]interface{}:
+			node[token] = value
+			return node, nil
+		case []interface{}:
+			i, err := index(token, len(node), true)
+			if err != nil {
+				return nil, err
+			}
+			node = append(node, nil)
+			copy(node[i+1:], node[i:])
+			node[i] = value
+			return node, nil
+		default:
+			return nil, fmt.Errorf("%w: %s not found", ErrInvalidPatch, pointer)
+		}
+	})
+}
+
+// remove deletes the value at pointer and returns the new document and
+// the removed value
+func remove(document interface{}, pointer string) (interface{}, interface{}, error) {
+	tokens, err := split(pointer)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, document, nil
+	}
+
+	var removed interface{}
+	document, err = update(document, tokens, pointer, func(parent interface{}, token string) (interface{}, error) {
+		switch node := parent.(type) {
+		case map[string]interface{}:
+			value, exists := node[token]
+			if !exists {
+				return nil, fmt.Errorf("%w: %s not found", ErrInvalidPatch, pointer)
+			}
+			removed = value
+			delete(node, token)
+			return node, nil
+		case []interface{}:
+			i, err := index(token, len(node), false)
+			if err != nil {
+				return nil, err
+			}
+			removed = node[i]
+			return append(node[:i:i], node[i+1:]...), nil
+		default:
+			return nil, fmt.Errorf("%w: %s not found", ErrInvalidPatch, pointer)
+		}
+	})
+	return document, removed, err
+}
+
+// update walks to the parent of the last token, lets change rewrite it and
+// stores the result back, since appending to an array yields a new slice
+func update(document interface{}, tokens []string, pointer string, change func(parent interface{}, token string) (interface{}, error)) (interface{}, error) {
+	if len(tokens) == 1 {
+		return change(document, tokens[0])
+	}
+
+	switch node := document.(type) {
+	case map[string]interface{}:
+		child, exists := node[tokens[0]]
+		if !exists {
+			return nil, fmt.Errorf("%w: %s not found", ErrInvalidPatch, pointer)
+		}
+		child, err := update(child, tokens[1:], pointer, change)
+		if err != nil {
+			return nil, err
+		}
+		node[tokens[0]] = child
+		return node, nil
+	case []interface{}:
+		i, err := index(tokens[0], len(node), false)
+		if err != nil {
+			return nil, err
+		}
+		child, err := update(node[i], tokens[1:], pointer, change)
+		if err != nil {
+			return nil, err
+		}
+		node[i] = child
+		return node, nil
+	default:
+		return nil, fmt.Errorf("%w: %s not found", ErrInvalidPatch, pointer)
+	}
+}
+
+// equal compares decoded JSON values, treating numbers by value
+func equal(a, b interface{}) bool {
+	if x, ok := a.(json.Number); ok {
+		y, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		if x == y {
+			return true
+		}
+		fx, errX := x.Float64()
+		fy, errY := y.Float64()
+		return errX == nil && errY == nil && fx == fy
+	}
+	return reflect.DeepEqual(a, b)
+}
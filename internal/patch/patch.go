@@ -0,0 +1,126 @@
+package patch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+
+	"github.com/calummacc/goblin/internal/validation"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	MergePatchType = "application/merge-patch+json"
+	JSONPatchType  = "application/json-patch+json"
+)
+
+var (
+	ErrUnsupportedType = errors.New("unsupported patch content type")
+	ErrInvalidPatch    = errors.New("invalid patch")
+	ErrTestFailed      = errors.New("patch test failed")
+)
+
+// Apply patches entity, a pointer to the current state of the resource,
+// with the request body. The body is a JSON Merge Patch (RFC 7396) or a
+// JSON Patch (RFC 6902) depending on Content-Type. The patched entity is
+// validated like a bound request, so a route's validation.Group applies.
+// On error entity is left unchanged; use Status to pick the response code.
+func Apply(c *gin.Context, entity interface{}) error {
+	mediaType, _, _ := mime.ParseMediaType(c.GetHeader("Content-Type"))
+	if mediaType != MergePatchType && mediaType != JSONPatchType {
+		return ErrUnsupportedType
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return err
+	}
+	current, err := json.Marshal(entity)
+	if err != nil {
+		return err
+	}
+
+	var patched []byte
+	if mediaType == MergePatchType {
+		patched, err = MergePatch(current, body)
+	} else {
+		patched, err = JSONPatch(current, body)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Decode into a fresh value so removed fields end up zero
+	target := reflect.New(reflect.TypeOf(entity).Elem())
+	if err := json.Unmarshal(patched, target.Interface()); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+	if err := validation.Validate(c, target.Interface()); err != nil {
+		return err
+	}
+	reflect.ValueOf(entity).Elem().Set(target.Elem())
+	return nil
+}
+
+// Status maps an error returned by Apply to an HTTP status
+func Status(err error) int {
+	switch {
+	case errors.Is(err, ErrUnsupportedType):
+		return http.StatusUnsupportedMediaType
+	case errors.Is(err, ErrTestFailed):
+		return http.StatusConflict
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// MergePatch applies an RFC 7396 merge patch to document
+func MergePatch(document, patch []byte) ([]byte, error) {
+	target, err := decode(document)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := decode(patch)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+	return json.Marshal(merge(target, changes))
+}
+
+func merge(target, patch interface{}) interface{} {
+	changes, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	fields, ok := target.(map[string]interface{})
+	if !ok {
+		fields = map[string]interface{}{}
+	}
+	for key, value := range changes {
+		if value == nil {
+			delete(fields, key)
+			continue
+		}
+		fields[key] = merge(fields[key], value)
+	}
+	return fields
+}
+
+func decode(data []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	if decoder.More() {
+		return nil, errors.New("trailing data after JSON value")
+	}
+	return value, nil
+}
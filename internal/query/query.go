@@ -0,0 +1,220 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrInvalidFilter     = errors.New("invalid filter")
+	ErrInvalidSort       = errors.New("invalid sort")
+	ErrFieldNotAllowed   = errors.New("field not allowed")
+	ErrTooManyConditions = errors.New("too many filter conditions")
+)
+
+type Operator string
+
+const (
+	Eq   Operator = "="
+	Ne   Operator = "!="
+	Gt   Operator = ">"
+	Gte  Operator = ">="
+	Lt   Operator = "<"
+	Lte  Operator = "<="
+	Like Operator = "~"
+)
+
+// operators is ordered so two-character operators are matched first
+var operators = []Operator{Gte, Lte, Ne, Eq, Gt, Lt, Like}
+
+type Condition struct {
+	Field string
+	Op    Operator
+	Value string
+}
+
+type Sort struct {
+	Field string
+	Desc  bool
+}
+
+// Query is a parsed ?filter= and ?sort= pair. Conditions are joined by AND.
+type Query struct {
+	Filters []Condition
+	Sort    []Sort
+}
+
+// parseFilter parses expressions such as `age>=18 AND name~"john doe"`.
+// Values containing spaces must be double quoted.
+func parseFilter(input string) ([]Condition, error) {
+	p := &parser{input: input}
+	conditions := make([]Condition, 0)
+
+	p.skipSpace()
+	if p.done() {
+		return conditions, nil
+	}
+
+	for {
+		condition, err := p.condition()
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, condition)
+
+		p.skipSpace()
+		if p.done() {
+			return conditions, nil
+		}
+		if !p.keyword("AND") {
+			return nil, p.errorf("expected AND")
+		}
+		p.skipSpace()
+	}
+}
+
+// parseSort parses a comma separated list of fields, "-" marking descending
+func parseSort(input string) ([]Sort, error) {
+	sorts := make([]Sort, 0)
+	if strings.TrimSpace(input) == "" {
+		return sorts, nil
+	}
+
+	for _, part := range strings.Split(input, ",") {
+		part = strings.TrimSpace(part)
+		sort := Sort{Field: part}
+		if field, found := strings.CutPrefix(part, "-"); found {
+			sort = Sort{Field: field, Desc: true}
+		} else if field, found := strings.CutPrefix(part, "+"); found {
+			sort.Field = field
+		}
+		if !isIdentifier(sort.Field) {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidSort, part)
+		}
+		sorts = append(sorts, sort)
+	}
+	return sorts, nil
+}
+
+type parser struct {
+	input string
+	pos   int
+}
+
+func (p *parser) done() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("%w at %d: %s", ErrInvalidFilter, p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) skipSpace() {
+	for !p.done() && p.input[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+func (p *parser) keyword(word string) bool {
+	end := p.pos + len(word)
+	if end > len(p.input) || !strings.EqualFold(p.input[p.pos:end], word) {
+		return false
+	}
+	if end < len(p.input) && p.input[end] != ' ' {
+		return false
+	}
+	p.pos = end
+	return true
+}
+
+func (p *parser) condition() (Condition, error) {
+	start := p.pos
+	for !p.done() && isIdentifierByte(p.input[p.pos], p.pos == start) {
+		p.pos++
+	}
+	if p.pos == start {
+		return Condition{}, p.errorf("expected field name")
+	}
+	field := p.input[start:p.pos]
+
+	p.skipSpace()
+	op, ok := p.operator()
+	if !ok {
+		return Condition{}, p.errorf("expected operator after %q", field)
+	}
+
+	p.skipSpace()
+	value, err := p.value()
+	if err != nil {
+		return Condition{}, err
+	}
+
+	return Condition{Field: field, Op: op, Value: value}, nil
+}
+
+func (p *parser) operator() (Operator, bool) {
+	for _, op := range operators {
+		if strings.HasPrefix(p.input[p.pos:], string(op)) {
+			p.pos += len(op)
+			return op, true
+		}
+	}
+	return "", false
+}
+
+func (p *parser) value() (string, error) {
+	if p.done() {
+		return "", p.errorf("expected value")
+	}
+
+	if p.input[p.pos] != '"' {
+		start := p.pos
+		for !p.done() && p.input[p.pos] != ' ' {
+			p.pos++
+		}
+		return p.input[start:p.pos], nil
+	}
+
+	p.pos++
+	var value strings.Builder
+	for !p.done() {
+		switch ch := p.input[p.pos]; ch {
+		case '\\':
+			if p.pos+1 >= len(p.input) {
+				return "", p.errorf("unterminated escape")
+			}
+			value.WriteByte(p.input[p.pos+1])
+			p.pos += 2
+		case '"':
+			p.pos++
+			return value.String(), nil
+		default:
+			value.WriteByte(ch)
+			p.pos++
+		}
+	}
+	return "", p.errorf("unterminated string")
+}
+
+func isIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if !isIdentifierByte(s[i], i == 0) {
+			return false
+		}
+	}
+	return true
+}
+
+func isIdentifierByte(ch byte, first bool) bool {
+	switch {
+	case ch >= 'a' && ch <= 'z', ch >= 'A' && ch <= 'Z', ch == '_':
+		return true
+	case ch >= '0' && ch <= '9', ch == '.':
+		return !first
+	}
+	return false
+}
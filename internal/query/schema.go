@@ -0,0 +1,239 @@
+package query
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const defaultMaxConditions = 10
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// Schema is the allowlist of fields a DTO exposes to ?filter= and ?sort=,
+// declared with `query:"filter,sort"` struct tags. Fields are named after
+// their json tag.
+type Schema struct {
+	fields        map[string]schemaField
+	MaxConditions int
+}
+
+type schemaField struct {
+	index  []int
+	typ    reflect.Type
+	filter bool
+	sort   bool
+}
+
+func NewSchema(dto interface{}) *Schema {
+	typ := reflect.TypeOf(dto)
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	schema := &Schema{
+		fields:        make(map[string]schemaField),
+		MaxConditions: defaultMaxConditions,
+	}
+	for _, field := range reflect.VisibleFields(typ) {
+		tag, ok := field.Tag.Lookup("query")
+		if !ok || !field.IsExported() {
+			continue
+		}
+
+		name := field.Name
+		if jsonName, _, _ := strings.Cut(field.Tag.Get("json"), ","); jsonName != "" && jsonName != "-" {
+			name = jsonName
+		}
+
+		entry := schemaField{index: field.Index, typ: field.Type}
+		for _, option := range strings.Split(tag, ",") {
+			switch strings.TrimSpace(option) {
+			case "filter":
+				entry.filter = true
+			case "sort":
+				entry.sort = true
+			}
+		}
+		schema.fields[name] = entry
+	}
+	return schema
+}
+
+// FromContext parses the request's filter and sort query parameters
+func (s *Schema) FromContext(c *gin.Context) (*Query, error) {
+	return s.Parse(c.Query("filter"), c.Query("sort"))
+}
+
+// Parse parses and validates a filter expression and sort list against the
+// schema, rejecting unknown fields, unsupported operators and values that
+// do not fit the field type.
+func (s *Schema) Parse(filter, sort string) (*Query, error) {
+	conditions, err := parseFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	if s.MaxConditions > 0 && len(conditions) > s.MaxConditions {
+		return nil, fmt.Errorf("%w: %d > %d", ErrTooManyConditions, len(conditions), s.MaxConditions)
+	}
+	for _, condition := range conditions {
+		field, exists := s.fields[condition.Field]
+		if !exists || !field.filter {
+			return nil, fmt.Errorf("%w: %s", ErrFieldNotAllowed, condition.Field)
+		}
+		if _, err := compare(reflect.Zero(field.typ), condition); err != nil {
+			return nil, err
+		}
+	}
+
+	sorts, err := parseSort(sort)
+	if err != nil {
+		return nil, err
+	}
+	for _, sort := range sorts {
+		if field, exists := s.fields[sort.Field]; !exists || !field.sort {
+			return nil, fmt.Errorf("%w: %s", ErrFieldNotAllowed, sort.Field)
+		}
+	}
+
+	return &Query{Filters: conditions, Sort: sorts}, nil
+}
+
+// Match reports whether item satisfies every condition of q. It is meant
+// for in-memory stores; database repositories translate q themselves.
+func (s *Schema) Match(q *Query, item interface{}) bool {
+	value := reflect.Indirect(reflect.ValueOf(item))
+	for _, condition := range q.Filters {
+		ok, err := compare(value.FieldByIndex(s.fields[condition.Field].index), condition)
+		if err != nil || !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Less orders a before b according to the sort list of q
+func (s *Schema) Less(q *Query, a, b interface{}) bool {
+	left := reflect.Indirect(reflect.ValueOf(a))
+	right := reflect.Indirect(reflect.ValueOf(b))
+	for _, sort := range q.Sort {
+		index := s.fields[sort.Field].index
+		order := order(left.FieldByIndex(index), right.FieldByIndex(index))
+		if order == 0 {
+			continue
+		}
+		return (order < 0) != sort.Desc
+	}
+	return false
+}
+
+// compare evaluates a condition against a field value
+func compare(value reflect.Value, condition Condition) (bool, error) {
+	var order int
+	switch {
+	case value.Type() == timeType:
+		operand, err := time.Parse(time.RFC3339, condition.Value)
+		if err != nil {
+			return false, invalidValue(condition)
+		}
+		order = value.Interface().(time.Time).Compare(operand)
+
+	case value.Kind() == reflect.String:
+		if condition.Op == Like {
+			return strings.Contains(strings.ToLower(value.String()), strings.ToLower(condition.Value)), nil
+		}
+		order = strings.Compare(value.String(), condition.Value)
+
+	case value.CanInt():
+		operand, err := strconv.ParseInt(condition.Value, 10, 64)
+		if err != nil {
+			return false, invalidValue(condition)
+		}
+		order = compareOrdered(value.Int(), operand)
+
+	case value.CanUint():
+		operand, err := strconv.ParseUint(condition.Value, 10, 64)
+		if err != nil {
+			return false, invalidValue(condition)
+		}
+		order = compareOrdered(value.Uint(), operand)
+
+	case value.CanFloat():
+		operand, err := strconv.ParseFloat(condition.Value, 64)
+		if err != nil {
+			return false, invalidValue(condition)
+		}
+		order = compareOrdered(value.Float(), operand)
+
+	case value.Kind() == reflect.Bool:
+		operand, err := strconv.ParseBool(condition.Value)
+		if err != nil || (condition.Op != Eq && condition.Op != Ne) {
+			return false, invalidValue(condition)
+		}
+		if value.Bool() != operand {
+			order = 1
+		}
+
+	default:
+		return false, fmt.Errorf("%w: %s cannot be filtered", ErrInvalidFilter, condition.Field)
+	}
+
+	switch condition.Op {
+	case Eq:
+		return order == 0, nil
+	case Ne:
+		return order != 0, nil
+	case Gt:
+		return order > 0, nil
+	case Gte:
+		return order >= 0, nil
+	case Lt:
+		return order < 0, nil
+	case Lte:
+		return order <= 0, nil
+	}
+	return false, fmt.Errorf("%w: %s does not support %s", ErrInvalidFilter, condition.Field, condition.Op)
+}
+
+func order(a, b reflect.Value) int {
+	switch {
+	case a.Type() == timeType:
+		return a.Interface().(time.Time).Compare(b.Interface().(time.Time))
+	case a.Kind() == reflect.String:
+		return strings.Compare(a.String(), b.String())
+	case a.CanInt():
+		return compareOrdered(a.Int(), b.Int())
+	case a.CanUint():
+		return compareOrdered(a.Uint(), b.Uint())
+	case a.CanFloat():
+		return compareOrdered(a.Float(), b.Float())
+	case a.Kind() == reflect.Bool:
+		return compareOrdered(boolInt(a.Bool()), boolInt(b.Bool()))
+	}
+	return 0
+}
+
+func compareOrdered[T int64 | uint64 | float64](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func boolInt(b bool) int64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func invalidValue(condition Condition) error {
+	return fmt.Errorf("%w: invalid value %q for %s", ErrInvalidFilter, condition.Value, condition.Field)
+}
@@ -0,0 +1,237 @@
+package serverless
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Request is an API Gateway REST (v1), HTTP API (v2) or ALB event. The
+// formats share enough fields to be decoded into one struct.
+type Request struct {
+	Version string `json:"version"`
+
+	// REST API and ALB
+	HTTPMethod                      string              `json:"httpMethod"`
+	Path                            string              `json:"path"`
+	MultiValueHeaders               map[string][]string `json:"multiValueHeaders"`
+	QueryStringParameters           map[string]string   `json:"queryStringParameters"`
+	MultiValueQueryStringParameters map[string][]string `json:"multiValueQueryStringParameters"`
+
+	// HTTP API
+	RawPath        string   `json:"rawPath"`
+	RawQueryString string   `json:"rawQueryString"`
+	Cookies        []string `json:"cookies"`
+
+	Headers         map[string]string `json:"headers"`
+	Body            string            `json:"body"`
+	IsBase64Encoded bool              `json:"isBase64Encoded"`
+
+	RequestContext struct {
+		HTTP struct {
+			Method   string `json:"method"`
+			SourceIP string `json:"sourceIp"`
+		} `json:"http"`
+		Identity struct {
+			SourceIP string `json:"sourceIp"`
+		} `json:"identity"`
+		ELB *struct {
+			TargetGroupArn string `json:"targetGroupArn"`
+		} `json:"elb"`
+	} `json:"requestContext"`
+}
+
+// Response is the proxy integration response for the matching Request
+type Response struct {
+	StatusCode        int                 `json:"statusCode"`
+	StatusDescription string              `json:"statusDescription,omitempty"`
+	Headers           map[string]string   `json:"headers,omitempty"`
+	MultiValueHeaders map[string][]string `json:"multiValueHeaders,omitempty"`
+	Cookies           []string            `json:"cookies,omitempty"`
+	Body              string              `json:"body"`
+	IsBase64Encoded   bool                `json:"isBase64Encoded"`
+}
+
+func (r *Request) httpAPI() bool {
+	return r.Version == "2.0"
+}
+
+// httpRequest turns the event into the request the pipeline serves
+func (r *Request) httpRequest(ctx context.Context) (*http.Request, error) {
+	target, err := r.url()
+	if err != nil {
+		return nil, err
+	}
+	method := r.HTTPMethod
+	if r.httpAPI() {
+		method = r.RequestContext.HTTP.Method
+	}
+
+	body := []byte(r.Body)
+	if r.IsBase64Encoded {
+		decoded, err := base64.StdEncoding.DecodeString(r.Body)
+		if err != nil {
+			return nil, err
+		}
+		body = decoded
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	for key, value := range r.Headers {
+		req.Header.Set(key, value)
+	}
+	for key, values := range r.MultiValueHeaders {
+		req.Header.Del(key)
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+	if len(r.Cookies) > 0 {
+		req.Header.Set("Cookie", strings.Join(r.Cookies, "; "))
+	}
+
+	req.Host = req.Header.Get("Host")
+	req.RequestURI = target.RequestURI()
+	if ip := r.sourceIP(); ip != "" {
+		req.RemoteAddr = ip + ":0"
+	}
+	return req, nil
+}
+
+// url builds the request URL. HTTP API events carry the path and query
+// still percent-encoded and are used as they are; REST API events carry
+// them decoded. ALB passes query parameters through as the client sent
+// them, so they are decoded before being encoded again.
+func (r *Request) url() (*url.URL, error) {
+	if r.httpAPI() {
+		target, err := url.ParseRequestURI(r.RawPath)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := url.ParseQuery(r.RawQueryString); err != nil {
+			return nil, err
+		}
+		target.RawQuery = r.RawQueryString
+		return target, nil
+	}
+
+	query := url.Values{}
+	if len(r.MultiValueQueryStringParameters) > 0 {
+		for key, values := range r.MultiValueQueryStringParameters {
+			query[key] = values
+		}
+	} else {
+		for key, value := range r.QueryStringParameters {
+			query.Set(key, value)
+		}
+	}
+	if r.RequestContext.ELB != nil {
+		decoded := url.Values{}
+		for key, values := range query {
+			key, err := url.QueryUnescape(key)
+			if err != nil {
+				return nil, err
+			}
+			for _, value := range values {
+				value, err := url.QueryUnescape(value)
+				if err != nil {
+					return nil, err
+				}
+				decoded.Add(key, value)
+			}
+		}
+		query = decoded
+	}
+	return &url.URL{Path: r.Path, RawQuery: query.Encode()}, nil
+}
+
+func (r *Request) sourceIP() string {
+	if r.httpAPI() {
+		return r.RequestContext.HTTP.SourceIP
+	}
+	return r.RequestContext.Identity.SourceIP
+}
+
+// response writes the recorded response in the format the event expects
+func (r *Request) response(rec *recorder) Response {
+	resp := Response{StatusCode: rec.status}
+
+	if text(rec.header.Get("Content-Type")) {
+		resp.Body = rec.body.String()
+	} else {
+		resp.Body = base64.StdEncoding.EncodeToString(rec.body.Bytes())
+		resp.IsBase64Encoded = true
+	}
+
+	switch {
+	case r.httpAPI():
+		resp.Headers = make(map[string]string, len(rec.header))
+		for key, values := range rec.header {
+			if key == "Set-Cookie" {
+				resp.Cookies = values
+				continue
+			}
+			resp.Headers[key] = strings.Join(values, ",")
+		}
+	case r.MultiValueHeaders != nil:
+		resp.MultiValueHeaders = rec.header
+	default:
+		resp.Headers = make(map[string]string, len(rec.header))
+		for key, values := range rec.header {
+			resp.Headers[key] = values[len(values)-1]
+		}
+	}
+
+	if r.RequestContext.ELB != nil {
+		resp.StatusDescription = http.StatusText(rec.status)
+	}
+	return resp
+}
+
+// text reports whether a body of contentType can be returned unencoded
+func text(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+	for _, prefix := range []string{"text/", "application/json", "application/xml", "application/x-ndjson", "application/javascript"} {
+		if strings.HasPrefix(contentType, prefix) {
+			return true
+		}
+	}
+	return strings.Contains(contentType, "+json") || strings.Contains(contentType, "+xml")
+}
+
+// recorder buffers a response for the event reply
+type recorder struct {
+	header http.Header
+	body   bytes.Buffer
+	status int
+}
+
+func newRecorder() *recorder {
+	return &recorder{header: make(http.Header)}
+}
+
+func (r *recorder) Header() http.Header {
+	return r.header
+}
+
+func (r *recorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+}
+
+func (r *recorder) Write(data []byte) (int, error) {
+	r.WriteHeader(http.StatusOK)
+	return r.body.Write(data)
+}
+
+func (r *recorder) Flush() {}
@@ -0,0 +1,182 @@
+package serverless
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/calummacc/goblin/internal/core"
+)
+
+const runtimeAPIVersion = "2018-06-01"
+
+var ErrNoRuntimeAPI = errors.New("AWS_LAMBDA_RUNTIME_API is not set")
+
+type Options struct {
+	// Flush runs after every invocation, before the sandbox may be frozen,
+	// to drain buffered logs, metrics or background work
+	Flush []func(ctx context.Context) error
+}
+
+func WithFlush(flush func(ctx context.Context) error) func(*Options) {
+	return func(opts *Options) {
+		opts.Flush = append(opts.Flush, flush)
+	}
+}
+
+// Adapter serves API Gateway and ALB events with an application's pipeline.
+// The application is initialized once, on the first invocation or in
+// Start, and never listens on a port. Google Cloud Functions and other
+// platforms that speak HTTP can mount Application.Handler directly.
+type Adapter struct {
+	app     *core.Application
+	options Options
+
+	once    sync.Once
+	handler http.Handler
+	initErr error
+}
+
+func NewAdapter(app *core.Application, opts ...func(*Options)) *Adapter {
+	options := Options{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return &Adapter{app: app, options: options}
+}
+
+func (a *Adapter) init() error {
+	a.once.Do(func() {
+		a.handler, a.initErr = a.app.Handler()
+	})
+	return a.initErr
+}
+
+// Invoke serves one event and returns the proxy response
+func (a *Adapter) Invoke(ctx context.Context, event Request) (Response, error) {
+	if err := a.init(); err != nil {
+		return Response{}, err
+	}
+
+	req, err := event.httpRequest(ctx)
+	if err != nil {
+		return Response{StatusCode: http.StatusBadRequest, Body: `{"error":"invalid event"}`}, nil
+	}
+
+	rec := newRecorder()
+	a.handler.ServeHTTP(rec, req)
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+
+	for _, flush := range a.options.Flush {
+		if err := flush(ctx); err != nil {
+			log.Printf("Flush after invocation failed: %v", err)
+		}
+	}
+	return event.response(rec), nil
+}
+
+// Start initializes the application and serves invocations from the Lambda
+// Runtime API until ctx ends, then shuts the application down
+func (a *Adapter) Start(ctx context.Context) error {
+	api := os.Getenv("AWS_LAMBDA_RUNTIME_API")
+	if api == "" {
+		return ErrNoRuntimeAPI
+	}
+	base := "http://" + api + "/" + runtimeAPIVersion + "/runtime"
+
+	if err := a.init(); err != nil {
+		postError(ctx, base+"/init/error", err)
+		return err
+	}
+
+	for ctx.Err() == nil {
+		if err := a.next(ctx, base); err != nil && ctx.Err() == nil {
+			log.Printf("Lambda runtime: %v", err)
+			time.Sleep(time.Second)
+		}
+	}
+	return a.app.Shutdown()
+}
+
+// next fetches one invocation, serves it and posts the result
+func (a *Adapter) next(ctx context.Context, base string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, base+"/invocation/next", nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	payload, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return err
+	}
+
+	id := resp.Header.Get("Lambda-Runtime-Aws-Request-Id")
+	invocationCtx, cancel := invocationContext(ctx, resp.Header.Get("Lambda-Runtime-Deadline-Ms"))
+	defer cancel()
+
+	var event Request
+	if err := json.Unmarshal(payload, &event); err != nil {
+		postError(ctx, base+"/invocation/"+id+"/error", err)
+		return nil
+	}
+	result, err := a.Invoke(invocationCtx, event)
+	if err != nil {
+		postError(ctx, base+"/invocation/"+id+"/error", err)
+		return nil
+	}
+
+	body, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	return post(ctx, base+"/invocation/"+id+"/response", body)
+}
+
+func invocationContext(ctx context.Context, deadlineMs string) (context.Context, context.CancelFunc) {
+	ms, err := strconv.ParseInt(deadlineMs, 10, 64)
+	if err != nil {
+		return context.WithCancel(ctx)
+	}
+	return context.WithDeadline(ctx, time.UnixMilli(ms))
+}
+
+func postError(ctx context.Context, url string, err error) {
+	body, _ := json.Marshal(map[string]string{
+		"errorMessage": err.Error(),
+		"errorType":    fmt.Sprintf("%T", err),
+	})
+	if postErr := post(ctx, url, body); postErr != nil {
+		log.Printf("Lambda runtime: reporting %v: %v", err, postErr)
+	}
+}
+
+func post(ctx context.Context, url string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("runtime API answered %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/calummacc/goblin/internal/core"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// groupKey is the gin context key holding the route's validation group
+const groupKey = "goblin.validation.group"
+
+var ErrEmptyBody = errors.New("request body is empty")
+
+// Validators holds the group validators of one application, created on
+// first use. Each application gets its own through ResolveValidators, so
+// rules registered by one never reach another.
+type Validators struct {
+	mu     sync.Mutex
+	groups map[string]*validator.Validate
+	rules  map[string]validator.Func
+}
+
+func NewValidators() *Validators {
+	return &Validators{
+		groups: make(map[string]*validator.Validate),
+		rules:  make(map[string]validator.Func),
+	}
+}
+
+// ResolveValidators returns the validators bound in container, binding new
+// ones on first use
+func ResolveValidators(container *core.Container) *Validators {
+	return core.ResolveOrBind(container, NewValidators)
+}
+
+// RegisterValidation adds a custom rule usable in every group tag
+func (v *Validators) RegisterValidation(tag string, fn validator.Func) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	for _, group := range v.groups {
+		if err := group.RegisterValidation(tag, fn); err != nil {
+			return err
+		}
+	}
+	v.rules[tag] = fn
+	return nil
+}
+
+// Group returns the validator reading its rules from the tag named group
+func (v *Validators) Group(group string) *validator.Validate {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	validate, exists := v.groups[group]
+	if !exists {
+		validate = validator.New(validator.WithRequiredStructEnabled())
+		validate.SetTagName(group)
+		for tag, fn := range v.rules {
+			validate.RegisterValidation(tag, fn)
+		}
+		v.groups[group] = validate
+	}
+	return validate
+}
+
+// Group selects the validation group BindJSON applies on a route, e.g.
+//
+//	users.POST("", validation.Group("create"), controller.CreateUser)
+//	users.PATCH("/:id", validation.Group("update"), controller.UpdateUser)
+func Group(name string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(groupKey, name)
+		c.Next()
+	}
+}
+
+// BindJSON decodes the request body into obj and validates it. Rules in
+// the `binding` tag apply to every request; rules in a tag named after the
+// group apply only when that group is selected, explicitly or with Group
+// on the route. The same DTO can then be fully required on create and
+// partially on update:
+//
+//	Email string `json:"email" binding:"omitempty,email" create:"required"`
+func BindJSON(c *gin.Context, obj interface{}, groups ...string) error {
+	if c.Request.Body == nil {
+		return ErrEmptyBody
+	}
+	if err := json.NewDecoder(c.Request.Body).Decode(obj); err != nil {
+		if errors.Is(err, io.EOF) {
+			return ErrEmptyBody
+		}
+		return err
+	}
+	return Validate(c, obj, groups...)
+}
+
+// Validate checks obj against its `binding` rules and the rules of the
+// given groups, or of the route's group when none are given. Group rules
+// come from the validators of the application serving c; outside an
+// application every call starts from fresh validators.
+func Validate(c *gin.Context, obj interface{}, groups ...string) error {
+	if binding.Validator != nil {
+		if err := binding.Validator.ValidateStruct(obj); err != nil {
+			return err
+		}
+	}
+
+	if len(groups) == 0 {
+		if group := c.GetString(groupKey); group != "" {
+			groups = []string{group}
+		}
+	}
+	if len(groups) == 0 {
+		return nil
+	}
+	validators := NewValidators()
+	if container := core.RequestContainer(c); container != nil {
+		validators = ResolveValidators(container)
+	}
+	for _, group := range groups {
+		if err := validators.Group(group).Struct(obj); err != nil {
+			return err
+		}
+	}
+	return nil
+}
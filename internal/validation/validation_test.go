@@ -0,0 +1,87 @@
+package validation
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/calummacc/goblin/internal/core"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+type signup struct {
+	Name string `json:"name" create:"required,notadmin"`
+}
+
+type signupModule struct {
+	core.BaseModule
+}
+
+func (m *signupModule) RegisterRoutes(router *gin.RouterGroup) {
+	router.POST("/signup", Group("create"), func(c *gin.Context) {
+		var body signup
+		if err := BindJSON(c, &body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.Status(http.StatusNoContent)
+	})
+}
+
+func compile(t *testing.T) *core.Application {
+	t.Helper()
+	module := core.NewTestingModule(&signupModule{})
+	app, err := module.Compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { module.Close() })
+	return app
+}
+
+func post(t *testing.T, app *core.Application, body string) int {
+	t.Helper()
+	handler, err := app.Handler()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/signup", strings.NewReader(body)))
+	return rec.Code
+}
+
+func TestValidatorsArePerApplication(t *testing.T) {
+	strict := compile(t)
+	if err := ResolveValidators(strict.GetContainer()).RegisterValidation("notadmin", func(fl validator.FieldLevel) bool {
+		return fl.Field().String() != "admin"
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := post(t, strict, `{"name": "admin"}`); got != http.StatusBadRequest {
+		t.Errorf("strict app: admin got %d, want 400", got)
+	}
+	if got := post(t, strict, `{"name": "ada"}`); got != http.StatusNoContent {
+		t.Errorf("strict app: ada got %d, want 204", got)
+	}
+	if got := post(t, strict, `{}`); got != http.StatusBadRequest {
+		t.Errorf("strict app: missing name got %d, want 400", got)
+	}
+
+	// The rule registered on the strict application must not leak: here
+	// the tag is unknown, which validator reports by panicking
+	other := compile(t)
+	if ResolveValidators(other.GetContainer()) == ResolveValidators(strict.GetContainer()) {
+		t.Fatal("applications share validators")
+	}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("other app: unknown rule did not panic, the strict app's rule leaked")
+			}
+		}()
+		ResolveValidators(other.GetContainer()).Group("create").Struct(&signup{Name: "admin"})
+	}()
+}